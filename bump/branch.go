@@ -0,0 +1,251 @@
+package bump
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"version-bump/console"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+)
+
+// DefaultReleaseBranchName is a template of release branches created on major and minor releases
+const DefaultReleaseBranchName string = "release/{{.Major}}.{{.Minor}}"
+
+// branchData is available to a release branch name template
+type branchData struct {
+	Major     string
+	Minor     string
+	Component string
+}
+
+// Branch returns the name of the current branch, or an empty name in a repository without commits
+func (g *GitConfig) Branch() (string, error) {
+	head, err := g.Repository.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "error resolving HEAD")
+	}
+
+	if !head.Name().IsBranch() {
+		return "", errors.New("refusing to release from a detached HEAD, checkout a branch")
+	}
+
+	return head.Name().Short(), nil
+}
+
+// onReleaseLine reports whether releases are made on a release branch, which maintains a single minor version:
+// a branch named like release branches are, or a release branch of the release policy
+func (b *Bump) onReleaseLine() (bool, error) {
+	branch, err := b.Git.Branch()
+	if err != nil || branch == "" {
+		return false, err
+	}
+
+	line, err := b.releaseLine(branch)
+	if err != nil || line != nil {
+		return line != nil, err
+	}
+
+	if !b.Configuration.Git.ReleasePolicy {
+		return false, nil
+	}
+
+	mainBranch, releases := b.policyBranches()
+	if branch == mainBranch {
+		return false, nil
+	}
+
+	return matchBranch(branch, releases)
+}
+
+// policyBranches returns the main branch and the pattern of release branches of the release policy
+func (b *Bump) policyBranches() (string, string) {
+	mainBranch := b.Configuration.Git.MainBranch
+	if mainBranch == "" {
+		mainBranch = DefaultMainBranch
+	}
+
+	releases := b.Configuration.Git.ReleaseBranches
+	if releases == "" {
+		releases = DefaultReleaseBranches
+	}
+
+	return mainBranch, releases
+}
+
+// checkBranch ensures that a release is allowed on the current branch,
+// and belongs to the release line of a release branch
+func (b *Bump) checkBranch(r *release) error {
+	level := r.Level
+
+	branch, err := b.Git.Branch()
+	if err != nil {
+		return err
+	}
+
+	if branch == "" {
+		return nil
+	}
+
+	options := b.Configuration.Git
+
+	if len(options.AllowedBranches) > 0 {
+		allowed, err := matchBranch(branch, options.AllowedBranches...)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return errors.Errorf("releasing from branch %v is not allowed, allowed branches: %v", branch, strings.Join(options.AllowedBranches, ", "))
+		}
+	}
+
+	if options.ReleaseBranch {
+		line, err := b.releaseLine(branch)
+		if err != nil {
+			return err
+		}
+
+		if line != nil && (r.Version.Major() != line.Major() || r.Version.Minor() != line.Minor()) {
+			return errors.Errorf("version %v does not belong to release line %v.%v of branch %v", r.Version, line.Major(), line.Minor(), branch)
+		}
+	}
+
+	if !options.ReleasePolicy {
+		return nil
+	}
+
+	mainBranch, releases := b.policyBranches()
+	if branch == mainBranch {
+		return nil
+	}
+
+	if level == Major || level == Minor {
+		return errors.Errorf("%v releases are only allowed on branch %v, current branch is %v", levelName(level), mainBranch, branch)
+	}
+
+	release, err := matchBranch(branch, releases)
+	if err != nil {
+		return err
+	}
+
+	if !release {
+		return errors.Errorf("patch releases are only allowed on branch %v or %v branches, current branch is %v", mainBranch, releases, branch)
+	}
+
+	return nil
+}
+
+// matchBranch reports whether a branch matches any of the glob patterns
+func matchBranch(branch string, patterns ...string) (bool, error) {
+	for _, p := range patterns {
+		ok, err := path.Match(p, branch)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid branch pattern %v", p)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// releaseBranchTemplate parses a release branch name template
+func (b *Bump) releaseBranchTemplate() (*template.Template, error) {
+	text := b.Configuration.Git.ReleaseBranchName
+	if text == "" {
+		text = DefaultReleaseBranchName
+	}
+
+	tmpl, err := template.New("release_branch_name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing release_branch_name template")
+	}
+
+	return tmpl, nil
+}
+
+// releaseBranchName returns the name of a release branch of a version
+func (b *Bump) releaseBranchName(version *semver.Version) (string, error) {
+	tmpl, err := b.releaseBranchTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	var name strings.Builder
+	data := branchData{
+		Major:     strconv.FormatUint(version.Major(), 10),
+		Minor:     strconv.FormatUint(version.Minor(), 10),
+		Component: b.Configuration.Git.Component,
+	}
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", errors.Wrap(err, "error rendering release_branch_name template, only .Major, .Minor and .Component are available")
+	}
+
+	if !plumbing.NewBranchReferenceName(name.String()).IsBranch() || strings.TrimSpace(name.String()) != name.String() || name.String() == "" {
+		return "", errors.Errorf("invalid release branch name: %q", name.String())
+	}
+
+	return name.String(), nil
+}
+
+// releaseLine returns the major and minor version of a release branch, or nil for any other branch
+func (b *Bump) releaseLine(branch string) (*semver.Version, error) {
+	tmpl, err := b.releaseBranchTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	const major, minor string = "\x00major\x00", "\x00minor\x00"
+
+	var pattern strings.Builder
+	data := branchData{
+		Major:     major,
+		Minor:     minor,
+		Component: b.Configuration.Git.Component,
+	}
+	if err := tmpl.Execute(&pattern, data); err != nil {
+		return nil, errors.Wrap(err, "error rendering release_branch_name template, only .Major, .Minor and .Component are available")
+	}
+
+	expression := regexp.QuoteMeta(pattern.String())
+	expression = strings.Replace(expression, major, "(?P<major>0|[1-9][0-9]*)", 1)
+	expression = strings.Replace(expression, minor, "(?P<minor>0|[1-9][0-9]*)", 1)
+
+	regex, err := regexp.Compile(fmt.Sprintf("^%v$", expression))
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing release_branch_name template")
+	}
+
+	match := regex.FindStringSubmatch(branch)
+	if match == nil || regex.SubexpIndex("major") < 0 || regex.SubexpIndex("minor") < 0 {
+		return nil, nil
+	}
+
+	return semver.NewVersion(fmt.Sprintf("%v.%v.0", match[regex.SubexpIndex("major")], match[regex.SubexpIndex("minor")]))
+}
+
+// branch creates a release branch of a release on a commit
+func (b *Bump) branch(r *release, hash plumbing.Hash) error {
+	if r.Branch == "" {
+		return nil
+	}
+
+	console.CreatingBranch(r.Branch)
+
+	if err := b.Git.References.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(r.Branch), hash)); err != nil {
+		return errors.Wrapf(err, "error creating release branch %v", r.Branch)
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package bump_test
+
+import (
+	"testing"
+	"time"
+	"version-bump/bump"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Branch        string
+		Detached      bool
+		Action        int
+		Options       bump.GitOptions
+		ExpectedTag   string
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Any Branch": {
+			Branch:      "feature/x",
+			Action:      bump.Minor,
+			ExpectedTag: "v1.3.0",
+		},
+		"Allowed Branch": {
+			Branch:      "release/1.2",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{AllowedBranches: []string{"main", "release/*"}},
+			ExpectedTag: "v1.2.4",
+		},
+		"Not Allowed Branch": {
+			Branch:        "feature/x",
+			Action:        bump.Minor,
+			Options:       bump.GitOptions{AllowedBranches: []string{"main", "release/*"}},
+			ExpectedError: "releasing from branch feature/x is not allowed, allowed branches: main, release/*",
+		},
+		"Detached HEAD": {
+			Branch:        "main",
+			Detached:      true,
+			Action:        bump.Patch,
+			ExpectedError: "refusing to release from a detached HEAD, checkout a branch",
+		},
+		"Policy - Minor on Main Branch": {
+			Branch:      "main",
+			Action:      bump.Minor,
+			Options:     bump.GitOptions{ReleasePolicy: true},
+			ExpectedTag: "v1.3.0",
+		},
+		"Policy - Patch on Main Branch": {
+			Branch:      "main",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleasePolicy: true},
+			ExpectedTag: "v1.2.4",
+		},
+		"Policy - Patch on Release Branch": {
+			Branch:      "release/1.2",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleasePolicy: true},
+			ExpectedTag: "v1.2.4",
+		},
+		"Policy - Major on Release Branch": {
+			Branch:        "release/1.2",
+			Action:        bump.Major,
+			Options:       bump.GitOptions{ReleasePolicy: true},
+			ExpectedError: "major releases are only allowed on branch main, current branch is release/1.2",
+		},
+		"Policy - Patch on Feature Branch": {
+			Branch:        "feature/x",
+			Action:        bump.Patch,
+			Options:       bump.GitOptions{ReleasePolicy: true},
+			ExpectedError: "patch releases are only allowed on branch main or release/* branches, current branch is feature/x",
+		},
+		"Policy - Custom Branches": {
+			Branch:      "maintenance-1.x",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleasePolicy: true, MainBranch: "master", ReleaseBranches: "maintenance-*"},
+			ExpectedTag: "v1.2.4",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.3")
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(test.Branch), Create: true}); err != nil {
+			t.Fatalf("error preparing test case: error creating branch: %v", err)
+		}
+
+		s := &object.Signature{Name: username, Email: email, When: time.Now()}
+		head, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+		if err != nil {
+			t.Fatalf("error preparing test case: error committing: %v", err)
+		}
+
+		if test.Detached {
+			if err := worktree.Checkout(&git.CheckoutOptions{Hash: head}); err != nil {
+				t.Fatalf("error preparing test case: error detaching HEAD: %v", err)
+			}
+		}
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   worktree,
+			},
+			Configuration: bump.Configuration{
+				Go:  bump.Language{Enabled: true, Directories: []string{"."}},
+				Git: test.Options,
+			},
+		}
+
+		err = r.Bump(test.Action)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		_, err = repo.Tag(test.ExpectedTag)
+		a.Equal(nil, err)
+	}
+}
+
+func TestReleaseBranch(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Branch string
+		Tag    string
+		// NOTE: a tag of a commit that is not reachable from the branch
+		Newer          string
+		Existing       string
+		Action         int
+		Options        bump.GitOptions
+		ExpectedTag    string
+		ExpectedBranch string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Minor Release": {
+			Branch:         "main",
+			Tag:            "v1.2.3",
+			Action:         bump.Minor,
+			Options:        bump.GitOptions{ReleaseBranch: true},
+			ExpectedTag:    "v1.3.0",
+			ExpectedBranch: "release/1.3",
+		},
+		"Major Release": {
+			Branch:         "main",
+			Tag:            "v1.2.3",
+			Action:         bump.Major,
+			Options:        bump.GitOptions{ReleaseBranch: true},
+			ExpectedTag:    "v2.0.0",
+			ExpectedBranch: "release/2.0",
+		},
+		"Patch Release": {
+			Branch:      "main",
+			Tag:         "v1.2.3",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleaseBranch: true},
+			ExpectedTag: "v1.2.4",
+		},
+		"Disabled": {
+			Branch:      "main",
+			Tag:         "v1.2.3",
+			Action:      bump.Minor,
+			ExpectedTag: "v1.3.0",
+		},
+		"Custom Name": {
+			Branch:         "main",
+			Tag:            "v1.2.3",
+			Action:         bump.Minor,
+			Options:        bump.GitOptions{ReleaseBranch: true, ReleaseBranchName: "maintenance/{{.Major}}.{{.Minor}}.x"},
+			ExpectedTag:    "v1.3.0",
+			ExpectedBranch: "maintenance/1.3.x",
+		},
+		"Branch Already Exists": {
+			Branch:        "main",
+			Tag:           "v1.2.3",
+			Existing:      "release/1.3",
+			Action:        bump.Minor,
+			Options:       bump.GitOptions{ReleaseBranch: true},
+			ExpectedError: "release branch release/1.3 already exists",
+		},
+		"Invalid Template": {
+			Branch:        "main",
+			Tag:           "v1.2.3",
+			Action:        bump.Minor,
+			Options:       bump.GitOptions{ReleaseBranch: true, ReleaseBranchName: "release/{{.Patch}}"},
+			ExpectedError: "error rendering release_branch_name template, only .Major, .Minor and .Component are available: template: release_branch_name:1:10: executing \"release_branch_name\" at <.Patch>: can't evaluate field Patch in type bump.branchData",
+		},
+		"Patch on Release Line": {
+			Branch:      "release/1.2",
+			Tag:         "v1.2.3",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleaseBranch: true},
+			ExpectedTag: "v1.2.4",
+		},
+		"Patch on Release Line after Newer Minor Release": {
+			Branch:      "release/0.2",
+			Tag:         "v0.2.1",
+			Newer:       "v0.3.0",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleaseBranch: true},
+			ExpectedTag: "v0.2.2",
+		},
+		"Policy - Patch on Release Branch after Newer Minor Release": {
+			Branch:      "release/0.2",
+			Tag:         "v0.2.1",
+			Newer:       "v0.3.0",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleasePolicy: true},
+			ExpectedTag: "v0.2.2",
+		},
+		"Policy - Patch on Custom Release Branch after Newer Minor Release": {
+			Branch:      "maintenance-0.2",
+			Tag:         "v0.2.1",
+			Newer:       "v0.3.0",
+			Action:      bump.Patch,
+			Options:     bump.GitOptions{ReleasePolicy: true, ReleaseBranches: "maintenance-*"},
+			ExpectedTag: "v0.2.2",
+		},
+		"Patch after Newer Minor Release": {
+			Branch:        "main",
+			Tag:           "v0.2.1",
+			Newer:         "v0.3.0",
+			Action:        bump.Patch,
+			Options:       bump.GitOptions{ReleaseBranch: true},
+			ExpectedError: "refusing to create tag v0.2.2: version is not greater than existing tag v0.3.0",
+		},
+		"Patch outside Release Line": {
+			Branch:        "release/1.2",
+			Tag:           "v1.3.0",
+			Action:        bump.Patch,
+			Options:       bump.GitOptions{ReleaseBranch: true},
+			ExpectedError: "version 1.3.1 does not belong to release line 1.2 of branch release/1.2",
+		},
+		"Patch on Custom Release Line": {
+			Branch:        "maintenance/1.2.x",
+			Tag:           "v1.3.0",
+			Action:        bump.Patch,
+			Options:       bump.GitOptions{ReleaseBranch: true, ReleaseBranchName: "maintenance/{{.Major}}.{{.Minor}}.x"},
+			ExpectedError: "version 1.3.1 does not belong to release line 1.2 of branch maintenance/1.2.x",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, test.Tag)
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		initial, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+
+		s := &object.Signature{Name: username, Email: email, When: time.Now()}
+
+		if test.Newer != "" {
+			newer, err := worktree.Commit("newer", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+			if err != nil {
+				t.Fatalf("error preparing test case: error committing: %v", err)
+			}
+
+			if _, err := repo.CreateTag(test.Newer, newer, nil); err != nil {
+				t.Fatalf("error preparing test case: error creating tag: %v", err)
+			}
+		}
+
+		if err := worktree.Checkout(&git.CheckoutOptions{Hash: initial.Hash(), Branch: plumbing.NewBranchReferenceName(test.Branch), Create: true}); err != nil {
+			t.Fatalf("error preparing test case: error creating branch: %v", err)
+		}
+
+		head, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+		if err != nil {
+			t.Fatalf("error preparing test case: error committing: %v", err)
+		}
+
+		if test.Existing != "" {
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(test.Existing), head)); err != nil {
+				t.Fatalf("error preparing test case: error creating branch: %v", err)
+			}
+		}
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   worktree,
+				References: repo.Storer,
+			},
+			Configuration: bump.Configuration{
+				Go:  bump.Language{Enabled: true, Directories: []string{"."}},
+				Git: test.Options,
+			},
+		}
+
+		err = r.Bump(test.Action)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		tagged, err := repo.ResolveRevision(plumbing.Revision(test.ExpectedTag))
+		a.Equal(nil, err)
+
+		branches, err := repo.Branches()
+		if err != nil {
+			t.Fatalf("error listing branches: %v", err)
+		}
+
+		var created []string
+		_ = branches.ForEach(func(ref *plumbing.Reference) error {
+			if ref.Name().Short() != test.Branch && ref.Name().Short() != "master" {
+				created = append(created, ref.Name().Short())
+				a.Equal(*tagged, ref.Hash())
+			}
+			return nil
+		})
+
+		if test.ExpectedBranch == "" {
+			a.Empty(created)
+		} else {
+			a.Equal([]string{test.ExpectedBranch}, created)
+		}
+	}
+}
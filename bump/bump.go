@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"version-bump/console"
@@ -12,7 +13,7 @@ import (
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/go-git/go-billy/v5"
 	git "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
 	"github.com/go-git/go-git/v5/storage/filesystem"
 	toml "github.com/pelletier/go-toml/v2"
@@ -30,10 +31,17 @@ func New(fs afero.Fs, meta, data billy.Filesystem, dir string) (*Bump, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "error opening repository")
 	}
-	localGitConfig, err := repo.ConfigScoped(config.GlobalScope)
+
+	var branch string
+	if head, err := repo.Reference(plumbing.HEAD, false); err == nil && head.Type() == plumbing.SymbolicReference {
+		branch = head.Target().Short()
+	}
+
+	settings, err := gitSettings(meta, branch)
 	if err != nil {
-		return nil, errors.Wrap(err, "error retrieving global git configuration")
+		return nil, errors.Wrap(err, "error retrieving git configuration")
 	}
+	author, committer := identities(settings)
 
 	worktree, err := repo.Worktree()
 	if err != nil {
@@ -71,12 +79,20 @@ func New(fs afero.Fs, meta, data billy.Filesystem, dir string) (*Bump, error) {
 			},
 		},
 		Git: GitConfig{
-			UserName:   localGitConfig.User.Name,
-			UserEmail:  localGitConfig.User.Email,
+			Author:     author,
+			Committer:  committer,
 			Repository: repo,
 			Worktree:   worktree,
+			Signing:    signing(settings),
+			References: repo.Storer,
+			Hooks: Hooks{
+				Dir:     hooksDir(settings, meta.Root(), data.Root()),
+				WorkDir: data.Root(),
+				GitDir:  meta.Root(),
+			},
 		},
 	}
+	o.Git.Worktree = &hookWorktree{Worktree: worktree, hooks: &o.Git.Hooks}
 
 	// check for config file
 	content, err := readFile(fs, ".bump")
@@ -91,7 +107,7 @@ func New(fs afero.Fs, meta, data billy.Filesystem, dir string) (*Bump, error) {
 
 	// parse config file
 	userConfig := new(Configuration)
-	if err := toml.Unmarshal([]byte(strings.Join(content, "\n")), userConfig); err != nil {
+	if err := toml.Unmarshal([]byte(content.Text()), userConfig); err != nil {
 		return nil, errors.Wrap(err, "error parsing project config file")
 	}
 
@@ -120,6 +136,31 @@ func New(fs afero.Fs, meta, data billy.Filesystem, dir string) (*Bump, error) {
 			Enabled:     userConfig.JavaScript.Enabled,
 			Directories: dirs,
 		},
+		Git: userConfig.Git,
+	}
+
+	if userConfig.Git.TagName != "" {
+		tags, err := NewTagFormat(userConfig.Git.TagName, userConfig.Git.Component)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing project config file")
+		}
+
+		o.Git.Tags = tags
+	}
+
+	switch userConfig.Git.Backend {
+	case "", BackendGoGit:
+	case BackendGit:
+		if err := o.Git.useGitCLI(repo, data.Root()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("error parsing project config file: unsupported git backend: %v", userConfig.Git.Backend)
+	}
+
+	// NOTE: templates are verified before they are used to release
+	if _, err := o.newRelease(semver.MustParse("1.0.0"), semver.MustParse("1.0.1"), Patch, []string{}); err != nil {
+		return nil, errors.Wrap(err, "error parsing project config file")
 	}
 
 	if len(userConfig.Docker.Directories) != 0 {
@@ -152,194 +193,531 @@ func New(fs afero.Fs, meta, data billy.Filesystem, dir string) (*Bump, error) {
 func (b *Bump) Bump(action int) error {
 	console.IncrementProjectVersion()
 
-	versions := make(map[string]int)
-	var version string
-	files := make([]string, 0)
+	found, unidentified, err := b.scan()
+	if err != nil {
+		return err
+	}
 
-	if b.Configuration.Docker.Enabled {
-		modifiedFiles, err := b.bumpComponent(langs.Docker, b.Configuration.Docker, action, versions, &version)
-		if err != nil {
-			return errors.Wrap(err, "error incrementing version in Docker project")
+	switch b.Configuration.Git.Source {
+	case "", SourceFiles:
+	case SourceTags:
+		return b.bumpFromTags(action, found, unidentified)
+	default:
+		return errors.Errorf("unsupported version source: %v", b.Configuration.Git.Source)
+	}
+
+	if len(found) == 0 {
+		return b.bumpTag(action)
+	}
+
+	changes, err := b.plan(found, func(v *semver.Version) *semver.Version {
+		next := increment(v, action)
+		return &next
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := validate(changes); err != nil {
+		return err
+	}
+
+	r, err := b.newRelease(changes[0].Version, changes[0].NewVersion, action, paths(changes))
+	if err != nil {
+		return err
+	}
+
+	return b.execute(changes, unidentified, r)
+}
+
+// bumpTag increments a version of a project without version files,
+// based on the latest release tag reachable from HEAD
+func (b *Bump) bumpTag(action int) error {
+	tag, current, err := b.Git.ReachableTag()
+	if err != nil {
+		return errors.Wrap(err, "error identifying latest release tag")
+	}
+
+	if current == nil {
+		return errors.New("version was not identified in files or tags")
+	}
+
+	next := increment(current, action)
+
+	return b.tagHead(tag, current, &next, action)
+}
+
+// tagHead releases a version by tagging HEAD, without modifying any file
+func (b *Bump) tagHead(tag string, current, next *semver.Version, action int) error {
+	if b.Configuration.Git.NoCommit || b.Configuration.Git.NoTag {
+		return errors.New("project without version files is released by a tag, which is disabled by --no-commit or --no-tag")
+	}
+
+	r, err := b.newRelease(current, next, action, []string{})
+	if err != nil {
+		return err
+	}
+
+	console.TagUpdate(tag, r.TagName)
+
+	// NOTE: an empty commit is a new release target, even when HEAD is tagged
+	if err := b.preflight(r, !b.Configuration.Git.EmptyCommit); err != nil {
+		return err
+	}
+
+	if b.DryRun {
+		console.DryRun()
+		console.WouldTag(r.TagName, b.Configuration.Git.EmptyCommit)
+
+		if r.Branch != "" {
+			console.WouldBranch(r.Branch)
 		}
 
-		files = append(files, modifiedFiles...)
+		if b.Configuration.Git.Push {
+			console.WouldPush(b.remote(), b.Configuration.Git.EmptyCommit, true)
+		}
+
+		return nil
 	}
 
-	if b.Configuration.Go.Enabled {
-		modifiedFiles, err := b.bumpComponent(langs.Go, b.Configuration.Go, action, versions, &version)
-		if err != nil {
-			return errors.Wrap(err, "error incrementing version in Go project")
+	console.TaggingChanges()
+
+	hash, err := b.Git.TagHead(r.CommitMessage, r.TagName, r.TagMessage, b.Configuration.Git.EmptyCommit)
+	if err != nil {
+		return errors.Wrap(err, "error tagging a version")
+	}
+
+	if err := b.branch(r, hash); err != nil {
+		return err
+	}
+
+	return b.publish(r, b.Configuration.Git.EmptyCommit)
+}
+
+// bumpFromTags increments a version of a project based on the highest release tag,
+// aligning all version files to it regardless of their current version
+func (b *Bump) bumpFromTags(action int, found []FileVersion, unidentified []string) error {
+	tag, current, err := b.Git.LatestTag()
+	if err != nil {
+		return errors.Wrap(err, "error identifying latest release tag")
+	}
+
+	if current == nil {
+		return errors.New("release tag was not found")
+	}
+
+	next := increment(current, action)
+	console.TagSource(tag)
+
+	if len(found) == 0 {
+		return b.tagHead(tag, current, &next, action)
+	}
+
+	outdated := make([]FileVersion, 0, len(found))
+	for _, f := range found {
+		if !f.Version.Equal(current) {
+			console.Drift(f.File, f.Version.String(), tag)
+		}
+
+		if !f.Version.Equal(&next) {
+			outdated = append(outdated, f)
 		}
+	}
 
-		files = append(files, modifiedFiles...)
+	if len(outdated) == 0 {
+		return b.tagHead(tag, current, &next, action)
 	}
 
-	if b.Configuration.JavaScript.Enabled {
-		modifiedFiles, err := b.bumpComponent(langs.JavaScript, b.Configuration.JavaScript, action, versions, &version)
-		if err != nil {
-			return errors.Wrap(err, "error incrementing version in JavaScript project")
+	changes, err := b.plan(outdated, func(*semver.Version) *semver.Version {
+		return &next
+	})
+	if err != nil {
+		return err
+	}
+
+	// NOTE: files are force-aligned to the tag, thus they may disagree with each other
+	for _, c := range changes {
+		if c.Content == c.Original {
+			return errors.Errorf("version was not changed in file %v", c.File)
 		}
+	}
 
-		files = append(files, modifiedFiles...)
+	r, err := b.newRelease(current, &next, action, paths(changes))
+	if err != nil {
+		return err
 	}
 
-	if len(versions) > 1 {
-		return errors.New("inconsistent versioning")
-	} else if len(versions) == 0 {
-		return errors.New("0 files updated")
+	printPlan(changes, unidentified)
+	if b.DryRun {
+		return b.preview(changes, r)
+	}
+
+	return b.apply(changes, r)
+}
+
+// Set writes an explicit version to all project files and commits the changes.
+// Unless forced, the version has to be greater than the current one.
+func (b *Bump) Set(version string, force bool) error {
+	newVersion, err := parseVersion(version)
+	if err != nil {
+		return err
+	}
+
+	found, unidentified, err := b.scan()
+	if err != nil {
+		return err
+	}
+
+	current, err := currentVersion(found)
+	if err != nil {
+		return err
+	}
+
+	if !force && !newVersion.GreaterThan(current) {
+		return errors.Errorf("version %v is not greater than current version %v", newVersion, current)
+	}
+
+	console.SetProjectVersion()
+
+	changes, err := b.plan(found, func(*semver.Version) *semver.Version {
+		return newVersion
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := validate(changes); err != nil {
+		return err
+	}
+
+	r, err := b.newRelease(current, newVersion, 0, paths(changes))
+	if err != nil {
+		return err
 	}
 
-	if len(files) != 0 {
-		// TODO: update changelog
-		console.CommittingChanges()
+	return b.execute(changes, unidentified, r)
+}
+
+// Sync aligns all project files to a single version without committing the changes.
+// When version is empty, the highest version found in the project files is used.
+func (b *Bump) Sync(version string) error {
+	found, _, err := b.scan()
+	if err != nil {
+		return err
+	}
+
+	if len(found) == 0 {
+		return errors.New("version was not identified")
+	}
+
+	var target *semver.Version
+	if version != "" {
+		target, err = parseVersion(version)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, f := range found {
+			if target == nil || f.Version.GreaterThan(target) {
+				target = f.Version
+			}
+		}
+	}
 
-		if err := b.Git.Save(files, version); err != nil {
-			return errors.Wrap(err, "error committing changes")
+	outdated := make([]FileVersion, 0)
+	for _, f := range found {
+		if !f.Version.Equal(target) {
+			outdated = append(outdated, f)
 		}
 	}
 
-	return nil
+	console.SyncProjectVersion(target.String())
+
+	if len(outdated) == 0 {
+		console.VersionsInSync()
+		return nil
+	}
+
+	changes, err := b.plan(outdated, func(*semver.Version) *semver.Version {
+		return target
+	})
+	if err != nil {
+		return err
+	}
+
+	// NOTE: files are expected to disagree, thus the plan is applied without validation
+	printPlan(changes, []string{})
+	if b.DryRun {
+		return b.preview(changes, nil)
+	}
+
+	return b.apply(changes, nil)
 }
 
-func (b *Bump) bumpComponent(name string, l Language, action int, versions map[string]int, version *string) ([]string, error) {
-	console.Language(name)
-	files := make([]string, 0)
+// Current returns the project version along with the version of each file.
+// Projects without version files, or configured to use tags as a version source,
+// are versioned by release tags.
+func (b *Bump) Current() (*semver.Version, []FileVersion, error) {
+	found, _, err := b.scan()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, dir := range l.Directories {
-		f, err := getFiles(b.FS, dir, l.ExcludeFiles)
+	if b.Configuration.Git.Source == SourceTags {
+		_, version, err := b.Git.LatestTag()
 		if err != nil {
-			return []string{}, errors.Wrap(err, "error listing directory files")
+			return nil, nil, errors.Wrap(err, "error identifying latest release tag")
 		}
 
-		langSettings := langs.New(name)
-		if langSettings == nil {
-			return []string{}, errors.New(fmt.Sprintf("not supported language: %v", name))
+		if version == nil {
+			return nil, nil, errors.New("release tag was not found")
 		}
 
-		modifiedFiles, err := b.incrementVersion(
-			dir,
-			filterFiles(langSettings.Files, f),
-			*langSettings,
-			action,
-			versions,
-			version,
-		)
+		return version, found, nil
+	}
+
+	if len(found) == 0 {
+		_, version, err := b.Git.ReachableTag()
 		if err != nil {
-			return []string{}, err
+			return nil, nil, errors.Wrap(err, "error identifying latest release tag")
 		}
 
-		files = append(files, modifiedFiles...)
+		if version != nil {
+			return version, found, nil
+		}
 	}
 
-	return files, nil
+	version, err := currentVersion(found)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return version, found, nil
 }
 
-func (b *Bump) incrementVersion(dir string, files []string, lang langs.Language, action int, versions map[string]int, version *string) ([]string, error) {
-	var identified bool
-	modifiedFiles := make([]string, 0)
+// Next returns the project version that an action would produce, without modifying any file
+func (b *Bump) Next(action int) (*semver.Version, *semver.Version, error) {
+	version, _, err := b.Current()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	for _, file := range files {
-		filepath := path.Join(dir, file)
-		fileContent, err := readFile(b.FS, filepath)
-		if err != nil {
-			return []string{}, errors.Wrapf(err, "error reading a file %v", file)
+	next := increment(version, action)
+	return version, &next, nil
+}
+
+// scan identifies the versions of all project files without modifying them.
+// It also returns the languages that have matching files, none of which contain a version.
+func (b *Bump) scan() ([]FileVersion, []string, error) {
+	res := make([]FileVersion, 0)
+	unidentified := make([]string, 0)
+
+	components := []struct {
+		Name     string
+		Language Language
+	}{
+		{langs.Docker, b.Configuration.Docker},
+		{langs.Go, b.Configuration.Go},
+		{langs.JavaScript, b.Configuration.JavaScript},
+	}
+
+	for _, c := range components {
+		if !c.Language.Enabled {
+			continue
 		}
-		var oldVersion *semver.Version
-
-		// get current version
-		if lang.Regex != nil {
-		outer:
-			for _, line := range fileContent {
-				for _, expression := range *lang.Regex {
-					regex := regexp.MustCompile(expression)
-					if regex.MatchString(line) {
-						oldVersion, err = semver.StrictNewVersion(regex.ReplaceAllString(line, "${1}"))
-						if err != nil {
-							return []string{}, errors.Wrapf(err, "error parsing semantic version at file %v", filepath)
-						}
-						break outer
-					}
-				}
-			}
+
+		langSettings := langs.New(c.Name)
+		if langSettings == nil {
+			return nil, nil, errors.New(fmt.Sprintf("not supported language: %v", c.Name))
 		}
 
-		if lang.JSONFields != nil {
-			for _, field := range *lang.JSONFields {
-				oldVersion, err = semver.StrictNewVersion(gjson.Get(strings.Join(fileContent, ""), field).String())
+		var candidates, identified int
+		for _, dir := range c.Language.Directories {
+			f, err := getFiles(b.FS, dir, c.Language.ExcludeFiles)
+			if err != nil {
+				return nil, nil, errors.Wrapf(errors.Wrap(err, "error listing directory files"), "error scanning %v project", c.Name)
+			}
+
+			for _, file := range filterFiles(langSettings.Files, f) {
+				candidates++
+				filepath := path.Join(dir, file)
+				content, err := readFile(b.FS, filepath)
 				if err != nil {
-					return []string{}, errors.Wrapf(err, "error parsing semantic version at file %v", filepath)
+					return nil, nil, errors.Wrapf(err, "error reading a file %v", filepath)
 				}
-				break
+
+				versions, err := getVersions(content, *langSettings)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "error parsing semantic version at file %v", filepath)
+				}
+
+				if len(versions) == 0 {
+					continue
+				}
+
+				for _, v := range versions[1:] {
+					if !v.Equal(versions[0]) {
+						return nil, nil, errors.Errorf("inconsistent versioning at file %v: %v and %v", filepath, versions[0], v)
+					}
+				}
+
+				identified++
+				res = append(res, FileVersion{
+					Language:    c.Name,
+					File:        filepath,
+					Version:     versions[0],
+					Occurrences: len(versions),
+				})
 			}
 		}
 
-		if oldVersion != nil {
-			var newVersion semver.Version
+		if candidates > 0 && identified == 0 {
+			unidentified = append(unidentified, c.Name)
+		}
+	}
 
-			switch action {
-			case 1:
-				newVersion = oldVersion.IncMajor()
-			case 2:
-				newVersion = oldVersion.IncMinor()
-			case 3:
-				newVersion = oldVersion.IncPatch()
-			}
+	return res, unidentified, nil
+}
 
-			console.VersionUpdate(oldVersion.String(), newVersion.String(), filepath)
-			*version = newVersion.String()
-			identified = true
-			versions[oldVersion.String()]++
-
-			// set future version
-			if lang.Regex != nil {
-				newContent := make([]string, 0)
-
-				for _, line := range fileContent {
-					var added bool
-					for _, expression := range *lang.Regex {
-						regex := regexp.MustCompile(expression)
-						if regex.MatchString(line) {
-							l := strings.ReplaceAll(line, oldVersion.String(), newVersion.String())
-							newContent = append(newContent, l)
-							added = true
-						}
+// getVersions returns every version occurrence of a document
+func getVersions(doc *document, lang langs.Language) ([]*semver.Version, error) {
+	res := make([]*semver.Version, 0)
+
+	if lang.Regex != nil {
+		for _, line := range doc.Lines {
+			for _, expression := range *lang.Regex {
+				regex := regexp.MustCompile(expression)
+				if regex.MatchString(line) {
+					v, err := semver.StrictNewVersion(regex.ReplaceAllString(line, "${1}"))
+					if err != nil {
+						return nil, err
 					}
 
-					if !added {
-						newContent = append(newContent, line)
-					}
+					res = append(res, v)
+					break
 				}
+			}
+		}
+	}
 
-				newContent = append(newContent, "")
-				if err := writeFile(b.FS, filepath, strings.Join(newContent, "\n")); err != nil {
-					return []string{}, errors.Wrapf(err, "error writing to file %v", filepath)
+	if lang.JSONFields != nil {
+		content := doc.Text()
+		for _, field := range *lang.JSONFields {
+			if value := gjson.Get(content, field); value.Exists() {
+				v, err := semver.StrictNewVersion(value.String())
+				if err != nil {
+					return nil, err
 				}
 
-				modifiedFiles = append(modifiedFiles, filepath)
+				res = append(res, v)
 			}
+		}
+	}
 
-			if lang.JSONFields != nil {
-				for _, field := range *lang.JSONFields {
-					if gjson.Get(strings.Join(fileContent, ""), field).Exists() {
-						newContent, err := sjson.Set(strings.Join(fileContent, "\n"), field, newVersion.String())
-						if err != nil {
-							return []string{}, errors.Wrapf(err, "error setting new version on content of a file %v", file)
-						}
+	return res, nil
+}
 
-						if err := writeFile(b.FS, filepath, newContent); err != nil {
-							return []string{}, errors.Wrapf(err, "error writing to file %v", filepath)
-						}
+// setVersion returns the exact content of a document with a new version, leaving all other bytes untouched.
+// It also returns the number of replaced occurrences.
+func setVersion(doc *document, lang langs.Language, oldVersion, newVersion string) (string, int, error) {
+	var occurrences int
+	res := &document{
+		BOM:   doc.BOM,
+		Lines: make([]string, 0, len(doc.Lines)),
+		EOLs:  doc.EOLs,
+		Mode:  doc.Mode,
+	}
 
-						modifiedFiles = append(modifiedFiles, filepath)
-					}
+	if lang.Regex != nil {
+		for _, line := range doc.Lines {
+			for _, expression := range *lang.Regex {
+				regex := regexp.MustCompile(expression)
+				if regex.MatchString(line) {
+					occurrences += strings.Count(line, oldVersion)
+					line = strings.ReplaceAll(line, oldVersion, newVersion)
+					break
 				}
 			}
+
+			res.Lines = append(res.Lines, line)
 		}
+
+		return res.String(), occurrences, nil
+	}
+
+	content := doc.Text()
+	if lang.JSONFields != nil {
+		for _, field := range *lang.JSONFields {
+			if gjson.Get(content, field).Exists() {
+				var err error
+				content, err = sjson.Set(content, field, newVersion)
+				if err != nil {
+					return "", 0, err
+				}
+
+				occurrences++
+			}
+		}
+	}
+
+	if doc.BOM {
+		return bom + content, occurrences, nil
+	}
+
+	return content, occurrences, nil
+}
+
+func increment(version *semver.Version, action int) semver.Version {
+	switch action {
+	case Major:
+		return version.IncMajor()
+	case Minor:
+		return version.IncMinor()
+	default:
+		return version.IncPatch()
+	}
+}
+
+// parseVersion validates a user provided semantic version, optionally prefixed with 'v'
+func parseVersion(version string) (*semver.Version, error) {
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V"))
+	if err != nil {
+		return nil, errors.Errorf("invalid semantic version: %v", version)
+	}
+
+	return v, nil
+}
+
+// currentVersion returns the project version if all files are consistent
+func currentVersion(files []FileVersion) (*semver.Version, error) {
+	if len(files) == 0 {
+		return nil, errors.New("version was not identified")
+	}
+
+	versions := make(map[string][]string)
+	for _, f := range files {
+		versions[f.Version.String()] = append(versions[f.Version.String()], f.File)
+	}
+
+	if len(versions) > 1 {
+		return nil, inconsistentVersions(versions)
+	}
+
+	return files[0].Version, nil
+}
+
+// inconsistentVersions describes which files disagree on the project version
+func inconsistentVersions(versions map[string][]string) error {
+	keys := make([]*semver.Version, 0, len(versions))
+	for v := range versions {
+		keys = append(keys, semver.MustParse(v))
 	}
+	sort.Sort(semver.Collection(keys))
 
-	if len(files) > 0 && !identified {
-		console.Error("    Version was not identified")
+	details := make([]string, 0, len(keys))
+	for _, v := range keys {
+		details = append(details, fmt.Sprintf("%v (%v)", v, strings.Join(versions[v.String()], ", ")))
 	}
 
-	return modifiedFiles, nil
+	return errors.Errorf("inconsistent versioning: %v", strings.Join(details, "; "))
 }
@@ -2,8 +2,11 @@ package bump_test
 
 import (
 	"fmt"
+	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 	"version-bump/bump"
 	"version-bump/mocks"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/filesystem"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -270,7 +274,7 @@ func TestBump(t *testing.T) {
 			MockAddError:       nil,
 			MockCommitError:    nil,
 			MockCreateTagError: nil,
-			ExpectedError:      "0 files updated",
+			ExpectedError:      "version was not identified in files or tags",
 		},
 		"Docker - Single, without Quotes": {
 			Version: "2.0.0",
@@ -705,7 +709,7 @@ func main() {
 			MockAddError:       nil,
 			MockCommitError:    nil,
 			MockCreateTagError: nil,
-			ExpectedError:      "error incrementing version in Docker project: error listing directory files: open dir: file does not exist",
+			ExpectedError:      "error scanning Docker project: error listing directory files: open dir: file does not exist",
 		},
 		"Go - Get Files Error": {
 			Version: "2.0.0",
@@ -720,7 +724,7 @@ func main() {
 			MockAddError:       nil,
 			MockCommitError:    nil,
 			MockCreateTagError: nil,
-			ExpectedError:      "error incrementing version in Go project: error listing directory files: open dir: file does not exist",
+			ExpectedError:      "error scanning Go project: error listing directory files: open dir: file does not exist",
 		},
 		"JavaScript - Get Files Error": {
 			Version: "2.0.0",
@@ -735,7 +739,7 @@ func main() {
 			MockAddError:       nil,
 			MockCommitError:    nil,
 			MockCreateTagError: nil,
-			ExpectedError:      "error incrementing version in JavaScript project: error listing directory files: open dir: file does not exist",
+			ExpectedError:      "error scanning JavaScript project: error listing directory files: open dir: file does not exist",
 		},
 		"Inconsistent Versioning": {
 			Version: "2.0.0",
@@ -796,7 +800,7 @@ func main() {
 			MockAddError:       nil,
 			MockCommitError:    nil,
 			MockCreateTagError: nil,
-			ExpectedError:      "inconsistent versioning",
+			ExpectedError:      "inconsistent versioning: 1.2.3 (Dockerfile); 1.3.0 (main.go)",
 		},
 		"Save Error": {
 			Version: "2.0.0",
@@ -929,18 +933,22 @@ const Version string = "1.2.3"`,
 
 		m1 := new(mocks.Repository)
 		m2 := new(mocks.Worktree)
+		clean(m2)
 
 		r := bump.Bump{
 			FS: afero.NewMemMapFs(),
 			Git: bump.GitConfig{
-				UserName:   username,
-				UserEmail:  email,
+				Author:     identity,
+				Committer:  identity,
 				Repository: m1,
 				Worktree:   m2,
 			},
 			Configuration: test.Configuration,
 		}
 
+		// NOTE: projects without version files are versioned by tags
+		untagged(m1)
+
 		shouldBeCommitted := false
 
 		if test.Configuration.Docker.Enabled {
@@ -1013,6 +1021,12 @@ const Version string = "1.2.3"`,
 		}
 
 		if shouldBeCommitted {
+			// NOTE: files are staged again after a rollback
+			times := 1
+			if test.MockAddError != nil || test.MockCommitError != nil {
+				times = 2
+			}
+
 			for dir, files := range test.Files.Docker {
 				for _, file := range files {
 					if file.ExpectedToBeChanged {
@@ -1022,7 +1036,7 @@ const Version string = "1.2.3"`,
 						} else {
 							f = path.Join(dir, file.Name)
 						}
-						m2.On("Add", f).Return(nil, test.MockAddError).Once()
+						m2.On("Add", f).Return(nil, test.MockAddError).Times(times)
 					}
 				}
 			}
@@ -1036,7 +1050,7 @@ const Version string = "1.2.3"`,
 						} else {
 							f = path.Join(dir, file.Name)
 						}
-						m2.On("Add", f).Return(nil, test.MockAddError).Once()
+						m2.On("Add", f).Return(nil, test.MockAddError).Times(times)
 					}
 				}
 			}
@@ -1050,7 +1064,7 @@ const Version string = "1.2.3"`,
 						} else {
 							f = path.Join(dir, file.Name)
 						}
-						m2.On("Add", f).Return(nil, test.MockAddError).Once()
+						m2.On("Add", f).Return(nil, test.MockAddError).Times(times)
 					}
 				}
 			}
@@ -1072,3 +1086,927 @@ const Version string = "1.2.3"`,
 		}
 	}
 }
+
+func TestSet(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Version         string
+		Force           bool
+		DryRun          bool
+		Files           map[string]string
+		ExpectedFiles   map[string]string
+		ExpectedCommit  bool
+		MockCommitError error
+		ExpectedError   string
+	}
+
+	dockerfile := func(version string) string {
+		return fmt.Sprintf("FROM scratch\nLABEL org.opencontainers.image.version=%v\n", version)
+	}
+
+	golang := func(version string) string {
+		return fmt.Sprintf("package main\n\nconst Version string = \"%v\"\n", version)
+	}
+
+	suite := map[string]test{
+		"Greater Version": {
+			Version: "1.5.0",
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.5.0"),
+				"main.go":    golang("1.5.0"),
+			},
+			ExpectedCommit: true,
+			ExpectedError:  "",
+		},
+		"Prefixed Version": {
+			Version: "v2.0.0",
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("2.0.0"),
+			},
+			ExpectedCommit: true,
+			ExpectedError:  "",
+		},
+		"Lower Version": {
+			Version: "1.0.0",
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedError: "version 1.0.0 is not greater than current version 1.2.3",
+		},
+		"Lower Version with Force": {
+			Version: "1.0.0",
+			Force:   true,
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("1.0.0"),
+			},
+			ExpectedCommit: true,
+			ExpectedError:  "",
+		},
+		"Dry Run": {
+			Version: "1.5.0",
+			DryRun:  true,
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedError: "",
+		},
+		"Invalid Version": {
+			Version: "1.2",
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedError: "invalid semantic version: 1.2",
+		},
+		"Inconsistent Versioning": {
+			Version: "2.0.0",
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.4"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.4"),
+			},
+			ExpectedError: "inconsistent versioning: 1.2.3 (Dockerfile); 1.2.4 (main.go)",
+		},
+		"Version Not Identified": {
+			Version:       "2.0.0",
+			Files:         map[string]string{},
+			ExpectedFiles: map[string]string{},
+			ExpectedError: "version was not identified",
+		},
+		"Save Error": {
+			Version: "2.0.0",
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedCommit:  true,
+			MockCommitError: errors.New("reason"),
+			ExpectedError:   "error committing changes: error committing changes: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		clean(m2)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+			DryRun: test.DryRun,
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Errorf("error preparing test case: error writing file %v: %v", f, err)
+				continue
+			}
+		}
+
+		if test.ExpectedCommit {
+			version := strings.TrimPrefix(test.Version, "v")
+			hash := plumbing.NewHash("abc")
+
+			times := 1
+			if test.MockCommitError != nil {
+				times = 2
+			}
+
+			for f := range test.Files {
+				m2.On("Add", f).Return(nil, nil).Times(times)
+			}
+
+			m2.On("Commit", version, mock.AnythingOfType("*git.CommitOptions")).Return(hash, test.MockCommitError).Once()
+			m1.On("CreateTag", fmt.Sprintf("v%v", version), hash, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, nil).Once()
+		}
+
+		err := r.Set(test.Version, test.Force)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		for f, c := range test.ExpectedFiles {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Version       string
+		DryRun        bool
+		Files         map[string]string
+		ExpectedFiles map[string]string
+		ExpectedError string
+	}
+
+	dockerfile := func(version string) string {
+		return fmt.Sprintf("FROM scratch\nLABEL org.opencontainers.image.version=%v\n", version)
+	}
+
+	golang := func(version string) string {
+		return fmt.Sprintf("package main\n\nconst Version string = \"%v\"\n", version)
+	}
+
+	suite := map[string]test{
+		"Highest Version": {
+			Version: "",
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.10.0"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.10.0"),
+				"main.go":    golang("1.10.0"),
+			},
+			ExpectedError: "",
+		},
+		"Chosen Version": {
+			Version: "1.2.3",
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.10.0"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedError: "",
+		},
+		"Dry Run": {
+			Version: "",
+			DryRun:  true,
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.10.0"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.10.0"),
+			},
+			ExpectedError: "",
+		},
+		"Already in Sync": {
+			Version: "",
+			Files: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"Dockerfile": dockerfile("1.2.3"),
+				"main.go":    golang("1.2.3"),
+			},
+			ExpectedError: "",
+		},
+		"Invalid Version": {
+			Version: "latest",
+			Files: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedFiles: map[string]string{
+				"main.go": golang("1.2.3"),
+			},
+			ExpectedError: "invalid semantic version: latest",
+		},
+		"Version Not Identified": {
+			Version:       "",
+			Files:         map[string]string{},
+			ExpectedFiles: map[string]string{},
+			ExpectedError: "version was not identified",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Configuration: bump.Configuration{
+				Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+			DryRun: test.DryRun,
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Errorf("error preparing test case: error writing file %v: %v", f, err)
+				continue
+			}
+		}
+
+		err := r.Sync(test.Version)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		for f, c := range test.ExpectedFiles {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Files           map[string]string
+		Action          int
+		ExpectedCurrent string
+		ExpectedNext    string
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Major": {
+			Files: map[string]string{
+				"Dockerfile": "LABEL org.opencontainers.image.version=1.2.3\n",
+				"main.go":    "const Version string = \"1.2.3\"\n",
+			},
+			Action:          bump.Major,
+			ExpectedCurrent: "1.2.3",
+			ExpectedNext:    "2.0.0",
+		},
+		"Minor": {
+			Files: map[string]string{
+				"package.json": `{"version": "1.2.3"}`,
+			},
+			Action:          bump.Minor,
+			ExpectedCurrent: "1.2.3",
+			ExpectedNext:    "1.3.0",
+		},
+		"Patch": {
+			Files: map[string]string{
+				"main.go": "const Version string = \"1.2.3\"\n",
+			},
+			Action:          bump.Patch,
+			ExpectedCurrent: "1.2.3",
+			ExpectedNext:    "1.2.4",
+		},
+		"Inconsistent Versioning": {
+			Files: map[string]string{
+				"Dockerfile": "LABEL org.opencontainers.image.version=1.2.3\n",
+				"main.go":    "const Version string = \"1.2.4\"\n",
+			},
+			Action:        bump.Patch,
+			ExpectedError: "inconsistent versioning: 1.2.3 (Dockerfile); 1.2.4 (main.go)",
+		},
+		"Version Not Identified": {
+			Files:         map[string]string{},
+			Action:        bump.Patch,
+			ExpectedError: "version was not identified",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Repository: repository(t),
+			},
+			Configuration: bump.Configuration{
+				Docker:     bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:         bump.Language{Enabled: true, Directories: []string{"."}},
+				JavaScript: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Errorf("error preparing test case: error writing file %v: %v", f, err)
+				continue
+			}
+		}
+
+		current, next, err := r.Next(test.Action)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		} else {
+			a.Equal(test.ExpectedCurrent, current.String())
+			a.Equal(test.ExpectedNext, next.String())
+
+			_, files, err := r.Current()
+			a.Equal(nil, err)
+			a.Equal(len(test.Files), len(files))
+		}
+
+		for f, c := range test.Files {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+	}
+}
+
+func TestBumpDryRun(t *testing.T) {
+	a := assert.New(t)
+
+	files := map[string]string{
+		"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.2.3\n",
+		"main.go":    "package main\n\nconst Version string = \"1.2.3\"\n",
+	}
+
+	// NOTE: mocks without write expectations fail the test if the repository is modified
+	m1 := new(mocks.Repository)
+	m2 := new(mocks.Worktree)
+	clean(m2)
+	untagged(m1)
+
+	r := bump.Bump{
+		FS: afero.NewMemMapFs(),
+		Git: bump.GitConfig{
+			Author:     identity,
+			Committer:  identity,
+			Repository: m1,
+			Worktree:   m2,
+		},
+		Configuration: bump.Configuration{
+			Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+			Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+		},
+		DryRun: true,
+	}
+	fs := r.FS
+
+	for f, c := range files {
+		if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file %v: %v", f, err)
+		}
+	}
+
+	a.Equal(nil, r.Bump(bump.Minor))
+	a.Equal(fs, r.FS)
+
+	for f, c := range files {
+		content, err := afero.ReadFile(r.FS, f)
+		a.Equal(nil, err)
+		a.Equal(c, string(content))
+	}
+}
+
+func TestBumpPreservesFormatting(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		File         string
+		Mode         os.FileMode
+		Content      string
+		ExpectedFile string
+	}
+
+	suite := map[string]test{
+		"CRLF Line Endings": {
+			File:         "Dockerfile",
+			Mode:         0644,
+			Content:      "FROM scratch\r\nLABEL org.opencontainers.image.version=1.2.3\r\nENTRYPOINT [ \"/app\" ]\r\n",
+			ExpectedFile: "FROM scratch\r\nLABEL org.opencontainers.image.version=1.3.0\r\nENTRYPOINT [ \"/app\" ]\r\n",
+		},
+		"Mixed Line Endings": {
+			File:         "main.go",
+			Mode:         0600,
+			Content:      "package main\r\n\nconst Version string = \"1.2.3\"\r\n",
+			ExpectedFile: "package main\r\n\nconst Version string = \"1.3.0\"\r\n",
+		},
+		"Byte Order Mark": {
+			File:         "main.go",
+			Mode:         0644,
+			Content:      "\xEF\xBB\xBFconst Version string = \"1.2.3\"\n",
+			ExpectedFile: "\xEF\xBB\xBFconst Version string = \"1.3.0\"\n",
+		},
+		"No Trailing Newline": {
+			File:         "main.go",
+			Mode:         0644,
+			Content:      "package main\n\nconst Version string = \"1.2.3\"",
+			ExpectedFile: "package main\n\nconst Version string = \"1.3.0\"",
+		},
+		"Executable": {
+			File:         "Dockerfile",
+			Mode:         0755,
+			Content:      "LABEL org.opencontainers.image.version=1.2.3\n",
+			ExpectedFile: "LABEL org.opencontainers.image.version=1.3.0\n",
+		},
+		"JSON with CRLF and BOM": {
+			File:         "package.json",
+			Mode:         0644,
+			Content:      "\xEF\xBB\xBF{\r\n  \"name\": \"app\",\r\n  \"version\": \"1.2.3\"\r\n}",
+			ExpectedFile: "\xEF\xBB\xBF{\r\n  \"name\": \"app\",\r\n  \"version\": \"1.3.0\"\r\n}",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		clean(m2)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Docker:     bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:         bump.Language{Enabled: true, Directories: []string{"."}},
+				JavaScript: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, test.File, []byte(test.Content), test.Mode); err != nil {
+			t.Errorf("error preparing test case: error writing file %v: %v", test.File, err)
+			continue
+		}
+
+		hash := plumbing.NewHash("abc")
+		m2.On("Add", test.File).Return(nil, nil).Once()
+		m2.On("Commit", "1.3.0", mock.AnythingOfType("*git.CommitOptions")).Return(hash, nil).Once()
+		m1.On("CreateTag", "v1.3.0", hash, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, nil).Once()
+
+		a.Equal(nil, r.Bump(bump.Minor))
+
+		content, err := afero.ReadFile(r.FS, test.File)
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedFile, string(content))
+
+		info, err := r.FS.Stat(test.File)
+		a.Equal(nil, err)
+		a.Equal(test.Mode, info.Mode().Perm())
+
+		// NOTE: temporary files should not remain
+		files, err := afero.ReadDir(r.FS, ".")
+		a.Equal(nil, err)
+		a.Equal(1, len(files))
+	}
+}
+
+func TestBumpLongLines(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		File            string
+		Content         string
+		ExpectedContent string
+		ExpectedError   string
+	}
+
+	literal := strings.Repeat("a", 1024*1024)
+	dependencies := strings.Repeat(`"dependency":"1.2.3",`, 10000)
+
+	suite := map[string]test{
+		"Go - Huge Literal": {
+			File:            "main.go",
+			Content:         fmt.Sprintf("package main\n\nvar data = \"%v\"\n\nconst Version string = \"1.2.3\"\n", literal),
+			ExpectedContent: fmt.Sprintf("package main\n\nvar data = \"%v\"\n\nconst Version string = \"1.2.4\"\n", literal),
+		},
+		"JavaScript - Minified": {
+			File:            "package.json",
+			Content:         fmt.Sprintf(`{"name":"app",%v"version":"1.2.3"}`, dependencies),
+			ExpectedContent: fmt.Sprintf(`{"name":"app",%v"version":"1.2.4"}`, dependencies),
+		},
+		"JavaScript - Escaped Version": {
+			File:            "package.json",
+			Content:         `{"name":"app","version":"1.2.\u0033"}`,
+			ExpectedContent: `{"name":"app","version":"1.2.\u0033"}`,
+			ExpectedError:   "refusing to write a file package.json: content changed beyond the version substitution",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		clean(m2)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Go:         bump.Language{Enabled: true, Directories: []string{"."}},
+				JavaScript: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, test.File, []byte(test.Content), 0644); err != nil {
+			t.Errorf("error preparing test case: error writing file %v: %v", test.File, err)
+			continue
+		}
+
+		hash := plumbing.NewHash("abc")
+		m2.On("Add", test.File).Return(nil, nil).Once()
+		m2.On("Commit", "1.2.4", mock.AnythingOfType("*git.CommitOptions")).Return(hash, nil).Once()
+		m1.On("CreateTag", "v1.2.4", hash, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, nil).Once()
+
+		err := r.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		content, err := afero.ReadFile(r.FS, test.File)
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedContent, string(content))
+	}
+}
+
+func TestBumpOccurrences(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		File            string
+		Content         string
+		ExpectedContent string
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Docker - Label and Environment Variable": {
+			File:            "Dockerfile",
+			Content:         "FROM scratch\nENV VERSION=1.2.3\nLABEL org.opencontainers.image.version=1.2.3\n",
+			ExpectedContent: "FROM scratch\nENV VERSION=1.2.4\nLABEL org.opencontainers.image.version=1.2.4\n",
+		},
+		"Docker - Environment Variable without Equal Sign": {
+			File:            "Dockerfile",
+			Content:         "FROM scratch\nENV GO_VERSION=1.2.3\nENV VERSION 1.2.3\n",
+			ExpectedContent: "FROM scratch\nENV GO_VERSION=1.2.3\nENV VERSION 1.2.4\n",
+		},
+		"Docker - Inconsistent Occurrences": {
+			File:            "Dockerfile",
+			Content:         "FROM scratch\nENV VERSION=1.2.2\nLABEL org.opencontainers.image.version=1.2.3\n",
+			ExpectedContent: "FROM scratch\nENV VERSION=1.2.2\nLABEL org.opencontainers.image.version=1.2.3\n",
+			ExpectedError:   "inconsistent versioning at file Dockerfile: 1.2.2 and 1.2.3",
+		},
+		"Go - Multiple Constants": {
+			File:            "main.go",
+			Content:         "package main\n\nconst Version string = \"1.2.3\"\n\nvar (\n\tversion string = \"1.2.3\"\n)\n",
+			ExpectedContent: "package main\n\nconst Version string = \"1.2.4\"\n\nvar (\n\tversion string = \"1.2.4\"\n)\n",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		clean(m2)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, test.File, []byte(test.Content), 0644); err != nil {
+			t.Errorf("error preparing test case: error writing file %v: %v", test.File, err)
+			continue
+		}
+
+		hash := plumbing.NewHash("abc")
+		m2.On("Add", test.File).Return(nil, nil).Once()
+		m2.On("Commit", "1.2.4", mock.AnythingOfType("*git.CommitOptions")).Return(hash, nil).Once()
+		m1.On("CreateTag", "v1.2.4", hash, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, nil).Once()
+
+		err := r.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		content, err := afero.ReadFile(r.FS, test.File)
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedContent, string(content))
+	}
+}
+
+func TestBumpFromTags(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Tags           []string
+		Files          map[string]string
+		Action         int
+		ExpectedFiles  map[string]string
+		ExpectedTag    string
+		ExpectedCommit bool
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Drift": {
+			Tags: []string{"v1.8.0", "v1.7.0"},
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.7.0\n",
+				"main.go":    "package main\n\nconst Version string = \"1.8.0\"\n",
+			},
+			Action: bump.Minor,
+			ExpectedFiles: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.9.0\n",
+				"main.go":    "package main\n\nconst Version string = \"1.9.0\"\n",
+			},
+			ExpectedTag:    "v1.9.0",
+			ExpectedCommit: true,
+		},
+		"Stale Files": {
+			Tags: []string{"v1.8.0"},
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.7.0\n",
+			},
+			Action: bump.Patch,
+			ExpectedFiles: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.8.1\n",
+			},
+			ExpectedTag:    "v1.8.1",
+			ExpectedCommit: true,
+		},
+		"Files Already Aligned": {
+			Tags: []string{"v1.8.0"},
+			Files: map[string]string{
+				"main.go": "package main\n\nconst Version string = \"1.8.1\"\n",
+			},
+			Action: bump.Patch,
+			ExpectedFiles: map[string]string{
+				"main.go": "package main\n\nconst Version string = \"1.8.1\"\n",
+			},
+			ExpectedTag:    "v1.8.1",
+			ExpectedCommit: false,
+		},
+		"No Version Files": {
+			Tags:           []string{"v1.8.0"},
+			Files:          map[string]string{},
+			Action:         bump.Major,
+			ExpectedFiles:  map[string]string{},
+			ExpectedTag:    "v2.0.0",
+			ExpectedCommit: false,
+		},
+		"No Release Tag": {
+			Tags: []string{"latest"},
+			Files: map[string]string{
+				"main.go": "package main\n\nconst Version string = \"1.8.0\"\n",
+			},
+			Action: bump.Patch,
+			ExpectedFiles: map[string]string{
+				"main.go": "package main\n\nconst Version string = \"1.8.0\"\n",
+			},
+			ExpectedError: "release tag was not found",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, test.Tags...)
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		// NOTE: changes since the latest release
+		s := &object.Signature{Name: username, Email: email, When: time.Now()}
+		head, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+		if err != nil {
+			t.Fatalf("error preparing test case: error committing: %v", err)
+		}
+
+		m := new(mocks.Worktree)
+		clean(m)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   m,
+			},
+			Configuration: bump.Configuration{
+				Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+				Git:    bump.GitOptions{Source: bump.SourceTags},
+			},
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Fatalf("error preparing test case: error writing file %v: %v", f, err)
+			}
+		}
+
+		if test.ExpectedCommit {
+			for f := range test.Files {
+				m.On("Add", f).Return(nil, nil).Once()
+			}
+			m.On("Commit", strings.TrimPrefix(test.ExpectedTag, "v"), mock.AnythingOfType("*git.CommitOptions")).Return(head, nil).Once()
+		}
+
+		err = r.Bump(test.Action)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		for f, c := range test.ExpectedFiles {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+
+		if test.ExpectedTag != "" {
+			_, err := repo.Tag(test.ExpectedTag)
+			a.Equal(nil, err)
+		}
+
+		m.AssertExpectations(t)
+	}
+}
+
+func TestBumpDirtyWorktree(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		AllowDirty      bool
+		ExpectedContent string
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Refuse Dirty Worktree": {
+			AllowDirty:      false,
+			ExpectedContent: "package main\n\nconst Version string = \"1.2.3\"\n",
+			ExpectedError:   "worktree has uncommitted changes, commit or stash them, or use --allow-dirty: README.md, docs/guide.md",
+		},
+		"Allow Dirty Worktree": {
+			AllowDirty:      true,
+			ExpectedContent: "package main\n\nconst Version string = \"1.2.4\"\n",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Go: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+			AllowDirty: test.AllowDirty,
+		}
+
+		if err := afero.WriteFile(r.FS, "main.go", []byte("package main\n\nconst Version string = \"1.2.3\"\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file: %v", err)
+		}
+
+		m2.On("Status").Return(git.Status{
+			"docs/guide.md": &git.FileStatus{Staging: git.Modified, Worktree: git.Unmodified},
+			"README.md":     &git.FileStatus{Staging: git.Unmodified, Worktree: git.Modified},
+			"notes.txt":     &git.FileStatus{Staging: git.Untracked, Worktree: git.Untracked},
+		}, nil)
+
+		if test.AllowDirty {
+			hash := plumbing.NewHash("abc")
+			m2.On("Restore", &git.RestoreOptions{Staged: true, Files: []string{"docs/guide.md"}}).Return(nil).Once()
+			m2.On("Add", "main.go").Return(nil, nil).Once()
+			m2.On("Commit", "1.2.4", mock.AnythingOfType("*git.CommitOptions")).Return(hash, nil).Once()
+			m1.On("CreateTag", "v1.2.4", hash, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, nil).Once()
+		}
+
+		err := r.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		content, err := afero.ReadFile(r.FS, "main.go")
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedContent, string(content))
+
+		m1.AssertExpectations(t)
+		m2.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,56 @@
+package bump
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Check verifies that all project files share a single version that matches the latest release tag.
+// Nothing is written, any discrepancy is reported as a problem of the result.
+func (b *Bump) Check() (*CheckResult, error) {
+	found, unidentified, err := b.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CheckResult{
+		Files:    found,
+		Problems: make([]string, 0),
+	}
+
+	// NOTE: a project without version files is versioned by release tags, thus it is consistent with them,
+	// even when its language files have no version, like a release of it is not refused
+	if len(found) == 0 {
+		tag, version, err := b.Git.ReachableTag()
+		if err != nil {
+			return nil, errors.Wrap(err, "error identifying latest release tag")
+		}
+
+		if version != nil {
+			res.Tag = tag
+			return res, nil
+		}
+	}
+
+	for _, l := range unidentified {
+		res.Problems = append(res.Problems, fmt.Sprintf("version was not identified in %v files", l))
+	}
+
+	version, err := currentVersion(found)
+	if err != nil {
+		res.Problems = append(res.Problems, err.Error())
+	}
+
+	tag, tagVersion, err := b.Git.LatestTag()
+	if err != nil {
+		return nil, errors.Wrap(err, "error identifying latest release tag")
+	}
+	res.Tag = tag
+
+	if version != nil && tagVersion != nil && !version.Equal(tagVersion) {
+		res.Problems = append(res.Problems, fmt.Sprintf("version %v does not match latest tag %v", version, tag))
+	}
+
+	return res, nil
+}
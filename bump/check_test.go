@@ -0,0 +1,130 @@
+package bump_test
+
+import (
+	"testing"
+	"version-bump/bump"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Files            map[string]string
+		Tags             []string
+		ExpectedTag      string
+		ExpectedFiles    int
+		ExpectedProblems []string
+	}
+
+	suite := map[string]test{
+		"Consistent without Tags": {
+			Files: map[string]string{
+				"Dockerfile": "LABEL org.opencontainers.image.version=1.2.3\n",
+				"main.go":    "const Version string = \"1.2.3\"\n",
+			},
+			Tags:             []string{},
+			ExpectedTag:      "",
+			ExpectedFiles:    2,
+			ExpectedProblems: []string{},
+		},
+		"Consistent with Tag": {
+			Files: map[string]string{
+				"main.go": "const Version string = \"1.2.3\"\n",
+			},
+			Tags:             []string{"v1.2.2", "v1.2.3"},
+			ExpectedTag:      "v1.2.3",
+			ExpectedFiles:    1,
+			ExpectedProblems: []string{},
+		},
+		"Tag Mismatch": {
+			Files: map[string]string{
+				"main.go": "const Version string = \"1.2.3\"\n",
+			},
+			Tags:          []string{"v1.3.0"},
+			ExpectedTag:   "v1.3.0",
+			ExpectedFiles: 1,
+			ExpectedProblems: []string{
+				"version 1.2.3 does not match latest tag v1.3.0",
+			},
+		},
+		"Inconsistent Versioning": {
+			Files: map[string]string{
+				"Dockerfile": "LABEL org.opencontainers.image.version=1.2.3\n",
+				"main.go":    "const Version string = \"1.2.4\"\n",
+			},
+			Tags:          []string{"v1.2.3"},
+			ExpectedTag:   "v1.2.3",
+			ExpectedFiles: 2,
+			ExpectedProblems: []string{
+				"inconsistent versioning: 1.2.3 (Dockerfile); 1.2.4 (main.go)",
+			},
+		},
+		"Version Not Identified": {
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\n",
+				"main.go":    "const Version string = \"1.2.3\"\n",
+			},
+			Tags:          []string{},
+			ExpectedTag:   "",
+			ExpectedFiles: 1,
+			ExpectedProblems: []string{
+				"version was not identified in Docker files",
+			},
+		},
+		"Tags Only": {
+			Files:            map[string]string{"lib.go": "package lib\n\nfunc Do() {}\n"},
+			Tags:             []string{"v1.2.2", "v1.2.3"},
+			ExpectedTag:      "v1.2.3",
+			ExpectedFiles:    0,
+			ExpectedProblems: []string{},
+		},
+		"No Files": {
+			Files:         map[string]string{},
+			Tags:          []string{},
+			ExpectedTag:   "",
+			ExpectedFiles: 0,
+			ExpectedProblems: []string{
+				"version was not identified",
+			},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Repository: repository(t, test.Tags...),
+			},
+			Configuration: bump.Configuration{
+				Docker: bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:     bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Errorf("error preparing test case: error writing file %v: %v", f, err)
+				continue
+			}
+		}
+
+		res, err := r.Check()
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedTag, res.Tag)
+		a.Equal(test.ExpectedFiles, len(res.Files))
+		a.Equal(test.ExpectedProblems, res.Problems)
+
+		for f, c := range test.Files {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// git backends
+const (
+	BackendGoGit string = "go-git"
+	BackendGit   string = "git"
+)
+
+// gitCLI runs the git binary in a worktree
+type gitCLI struct {
+	Dir string
+	// NOTE: commits and tags are signed by git, with the signing settings of a project
+	Signing *Signing
+	// NOTE: git runs hooks by itself
+	Hooks *Hooks
+}
+
+// run executes a git command, feeding stdin to it, and returns its output
+func (c *gitCLI) run(stdin string, env []string, command string, args ...string) (string, error) {
+	return c.runWith(nil, stdin, env, command, args...)
+}
+
+// runWith executes a git command like run does, overriding git configuration with '-c key=value' options
+func (c *gitCLI) runWith(config []string, stdin string, env []string, command string, args ...string) (string, error) {
+	argv := make([]string, 0, len(config)+len(args)+1)
+	argv = append(argv, config...)
+	argv = append(argv, command)
+	argv = append(argv, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", argv...)
+	cmd.Dir = c.Dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+
+		return "", errors.Errorf("git %v failed: %v", command, message)
+	}
+
+	return stdout.String(), nil
+}
+
+// signingArgs overrides signing settings of git configuration with the settings of a project
+func (c *gitCLI) signingArgs() []string {
+	args := make([]string, 0)
+
+	if c.Signing.Key != "" {
+		args = append(args, "-c", fmt.Sprintf("user.signingkey=%v", c.Signing.Key))
+	}
+
+	if c.Signing.Format != "" {
+		args = append(args, "-c", fmt.Sprintf("gpg.format=%v", c.Signing.Format))
+	}
+
+	return args
+}
+
+// identityEnv sets an identity of a git command
+func identityEnv(role string, sign *object.Signature) []string {
+	if sign == nil {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("GIT_%v_NAME=%v", role, sign.Name),
+		fmt.Sprintf("GIT_%v_EMAIL=%v", role, sign.Email),
+	}
+}
+
+// cliRepository reads a repository with go-git and modifies it with the git binary
+type cliRepository struct {
+	*git.Repository
+	cli *gitCLI
+}
+
+func (r *cliRepository) CreateTag(name string, hash plumbing.Hash, opts *git.CreateTagOptions) (*plumbing.Reference, error) {
+	args := make([]string, 0)
+
+	var message string
+	var env []string
+	if opts != nil {
+		if r.cli.Signing.Tag {
+			args = append(args, "--sign")
+		} else {
+			args = append(args, "--annotate", "--no-sign")
+		}
+
+		// NOTE: a tag message ends with a newline, like go-git writes it
+		args = append(args, "--cleanup=verbatim", "--file=-")
+		message = strings.TrimRight(opts.Message, "\n") + "\n"
+		env = identityEnv("COMMITTER", opts.Tagger)
+	} else {
+		args = append(args, "--no-sign")
+	}
+
+	if _, err := r.cli.runWith(r.cli.signingArgs(), message, env, "tag", append(args, name, hash.String())...); err != nil {
+		return nil, err
+	}
+
+	return r.Repository.Reference(plumbing.NewTagReferenceName(name), false)
+}
+
+// Push pushes refspecs with the git binary, which authenticates with credential helpers of git configuration
+func (r *cliRepository) Push(opts *git.PushOptions) error {
+	args := []string{"--porcelain", opts.RemoteName}
+	for _, s := range opts.RefSpecs {
+		args = append(args, s.String())
+	}
+
+	_, err := r.cli.run("", nil, "push", args...)
+	return err
+}
+
+// Fetch fetches a remote with the git binary, without tags unless they are requested
+func (r *cliRepository) Fetch(opts *git.FetchOptions) error {
+	args := []string{"--quiet"}
+	if opts.Tags == git.NoTags {
+		args = append(args, "--no-tags")
+	}
+
+	_, err := r.cli.run("", nil, "fetch", append(args, opts.RemoteName)...)
+	return err
+}
+
+// listRemote lists references of a remote with the git binary
+func (r *cliRepository) listRemote(remote string) ([]*plumbing.Reference, error) {
+	out, err := r.cli.run("", nil, "ls-remote", "--refs", remote)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*plumbing.Reference, 0)
+	for _, line := range strings.Split(out, "\n") {
+		hash, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		refs = append(refs, plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(hash)))
+	}
+
+	return refs, nil
+}
+
+// cliWorktree modifies a worktree with the git binary
+type cliWorktree struct {
+	cli *gitCLI
+}
+
+func (w *cliWorktree) Add(path string) (plumbing.Hash, error) {
+	if _, err := w.cli.run("", nil, "add", "--", path); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return plumbing.ZeroHash, nil
+}
+
+func (w *cliWorktree) Commit(message string, opts *git.CommitOptions) (plumbing.Hash, error) {
+	args := []string{"--cleanup=verbatim", "--file=-"}
+
+	if w.cli.Signing.Commit {
+		args = append(args, "--gpg-sign")
+	} else {
+		args = append(args, "--no-gpg-sign")
+	}
+
+	if w.cli.Hooks.NoVerify {
+		args = append(args, "--no-verify")
+	}
+
+	if opts.AllowEmptyCommits {
+		args = append(args, "--allow-empty")
+	}
+
+	env := append(identityEnv("AUTHOR", opts.Author), identityEnv("COMMITTER", opts.Committer)...)
+
+	// NOTE: a commit message ends with a newline, like 'git commit -m' writes it, thus commit-msg hooks may append lines to it
+	if _, err := w.cli.runWith(w.cli.signingArgs(), strings.TrimRight(message, "\n")+"\n", env, "commit", args...); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	hash, err := w.cli.run("", nil, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return plumbing.NewHash(strings.TrimSpace(hash)), nil
+}
+
+// Status reads a worktree status, where staging and worktree codes of git match go-git ones
+func (w *cliWorktree) Status() (git.Status, error) {
+	out, err := w.cli.run("", nil, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(git.Status)
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		s := &git.FileStatus{
+			Staging:  git.StatusCode(entry[0]),
+			Worktree: git.StatusCode(entry[1]),
+		}
+
+		// NOTE: a renamed or copied file is followed by its original path
+		if s.Staging == git.Renamed || s.Staging == git.Copied {
+			i++
+			if i < len(entries) {
+				s.Extra = entries[i]
+			}
+		}
+
+		status[entry[3:]] = s
+	}
+
+	return status, nil
+}
+
+func (w *cliWorktree) Restore(opts *git.RestoreOptions) error {
+	args := make([]string, 0)
+	if opts.Staged {
+		args = append(args, "--staged")
+	}
+	if opts.Worktree {
+		args = append(args, "--worktree")
+	}
+
+	_, err := w.cli.run("", nil, "restore", append(append(args, "--"), opts.Files...)...)
+	return err
+}
+
+// useGitCLI replaces go-git write operations of a repository with the git binary
+func (g *GitConfig) useGitCLI(repo *git.Repository, dir string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.Wrap(err, "git backend requires a git binary")
+	}
+
+	cli := &gitCLI{Dir: dir, Signing: &g.Signing, Hooks: &g.Hooks}
+
+	g.Backend = BackendGit
+	g.Repository = &cliRepository{Repository: repo, cli: cli}
+	g.Worktree = &cliWorktree{cli: cli}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package bump_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// project initializes a repository on disk with a Go version file, a README file and a project configuration file,
+// released as v1.2.3 with a commit on top of the release
+func project(t *testing.T, config string) (string, *git.Repository) {
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("error preparing test case: error initializing repository: %v", err)
+	}
+
+	files := map[string]string{
+		"version.go": "package main\n\nconst Version string = \"1.2.3\"\n",
+		"README.md":  "# Project\n",
+		".bump":      "[go]\nenabled = true\n\n[git]\n" + config,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing %v: %v", name, err)
+		}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	if err := worktree.AddGlob("."); err != nil {
+		t.Fatalf("error preparing test case: error staging files: %v", err)
+	}
+
+	s := &object.Signature{Name: username, Email: email, When: time.Now()}
+	hash, err := worktree.Commit("initial", &git.CommitOptions{Author: s, Committer: s})
+	if err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	if _, err := repo.CreateTag("v1.2.3", hash, nil); err != nil {
+		t.Fatalf("error preparing test case: error tagging: %v", err)
+	}
+
+	if _, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s}); err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv(bump.AuthorNameVariable, "author")
+	t.Setenv(bump.AuthorEmailVariable, "author@domain.com")
+	t.Setenv(bump.CommitterNameVariable, "committer")
+	t.Setenv(bump.CommitterEmailVariable, "committer@domain.com")
+
+	return dir, repo
+}
+
+func TestGitBackend(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	a := assert.New(t)
+
+	type test struct {
+		Config             string
+		SSHSigning         bool
+		Modified           bool
+		Staged             bool
+		AllowDirty         bool
+		ExpectedFiles      []string
+		ExpectedTagMessage string
+		ExpectedError      string
+	}
+
+	suite := map[string]test{
+		"Release": {
+			Config:             "backend = 'git'\n",
+			ExpectedFiles:      []string{"version.go"},
+			ExpectedTagMessage: "1.2.4",
+		},
+		"Templates": {
+			Config:             "backend = 'git'\ncommit_message = 'chore(release): {{.Version}}'\ntag_message = 'Release {{.Version}}'\n",
+			ExpectedFiles:      []string{"version.go"},
+			ExpectedTagMessage: "Release 1.2.4",
+		},
+		"Lightweight Tag": {
+			Config:        "backend = 'git'\ntag_type = 'lightweight'\n",
+			ExpectedFiles: []string{"version.go"},
+		},
+		"Signed": {
+			Config:             "backend = 'git'\n",
+			SSHSigning:         true,
+			ExpectedFiles:      []string{"version.go"},
+			ExpectedTagMessage: "1.2.4",
+		},
+		"Signed Lightweight Tag": {
+			Config:        "backend = 'git'\ntag_type = 'lightweight'\n",
+			SSHSigning:    true,
+			ExpectedError: "lightweight tags can not be signed, use tag_type = 'annotated' or disable tag signing",
+		},
+		"Dirty Worktree": {
+			Config:        "backend = 'git'\n",
+			Modified:      true,
+			ExpectedError: "worktree has uncommitted changes, commit or stash them, or use --allow-dirty: README.md",
+		},
+		"Allow Dirty Worktree": {
+			Config:             "backend = 'git'\n",
+			Staged:             true,
+			AllowDirty:         true,
+			ExpectedFiles:      []string{"version.go"},
+			ExpectedTagMessage: "1.2.4",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, repo := project(t, test.Config)
+
+		if test.Modified || test.Staged {
+			if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+				t.Fatalf("error preparing test case: error writing README.md: %v", err)
+			}
+		}
+
+		if test.Staged {
+			if out, err := exec.Command("git", "-C", dir, "add", "README.md").CombinedOutput(); err != nil {
+				t.Fatalf("error preparing test case: error staging README.md: %v: %s", err, out)
+			}
+		}
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+		b.AllowDirty = test.AllowDirty
+
+		if test.SSHSigning {
+			b.Git.Signing = bump.Signing{Commit: true, Tag: true, Format: bump.FormatSSH, Key: sshKey(t)}
+		}
+
+		err = b.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		tagged, err := repo.ResolveRevision(plumbing.Revision("v1.2.4"))
+		if err != nil {
+			t.Fatalf("error retrieving tag: %v", err)
+		}
+
+		commit, err := repo.CommitObject(*tagged)
+		if err != nil {
+			t.Fatalf("error retrieving commit: %v", err)
+		}
+
+		a.Equal("author", commit.Author.Name)
+		a.Equal("author@domain.com", commit.Author.Email)
+		a.Equal("committer", commit.Committer.Name)
+		a.Equal("committer@domain.com", commit.Committer.Email)
+
+		stats, err := commit.Stats()
+		if err != nil {
+			t.Fatalf("error retrieving commit files: %v", err)
+		}
+
+		files := make([]string, 0)
+		for _, s := range stats {
+			files = append(files, s.Name)
+		}
+		sort.Strings(files)
+		a.Equal(test.ExpectedFiles, files)
+
+		ref, err := repo.Tag("v1.2.4")
+		if err != nil {
+			t.Fatalf("error retrieving tag: %v", err)
+		}
+
+		tag, err := repo.TagObject(ref.Hash())
+		if test.ExpectedTagMessage == "" {
+			a.Equal(plumbing.ErrObjectNotFound, err)
+		} else if a.Equal(nil, err) {
+			a.Equal(test.ExpectedTagMessage+"\n", tag.Message)
+			a.Equal("committer", tag.Tagger.Name)
+
+			if test.SSHSigning {
+				a.Contains(tag.PGPSignature, "-----BEGIN SSH SIGNATURE-----")
+			}
+		}
+
+		if test.SSHSigning {
+			out, err := exec.Command("git", "-C", dir, "cat-file", "commit", tagged.String()).CombinedOutput()
+			a.Equal(nil, err)
+			a.Contains(string(out), "gpgsig -----BEGIN SSH SIGNATURE-----")
+		}
+
+		if test.Staged {
+			out, err := exec.Command("git", "-C", dir, "status", "--porcelain").CombinedOutput()
+			a.Equal(nil, err)
+			a.Equal(" M README.md\n", string(out))
+		}
+	}
+}
+
+func TestUnsupportedBackend(t *testing.T) {
+	dir, _ := project(t, "backend = 'svn'\n")
+
+	_, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+	assert.EqualError(t, err, "error parsing project config file: unsupported git backend: svn")
+}
+
+func TestGitBackendSigningError(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir, _ := project(t, "backend = 'git'\n")
+
+	b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+	if err != nil {
+		t.Fatalf("error preparing test case: %v", err)
+	}
+	b.Git.Signing = bump.Signing{Commit: true, Tag: true, Format: bump.FormatSSH, Key: filepath.Join(dir, "missing")}
+
+	// NOTE: signing overrides precede the git subcommand, which is reported instead of them
+	err = b.Bump(bump.Patch)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error committing changes: error committing changes: git commit failed: ")
+	}
+}
@@ -0,0 +1,91 @@
+package bump
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5/storage/filesystem/dotgit"
+	"github.com/pkg/errors"
+)
+
+// Location is a discovered repository
+type Location struct {
+	// NOTE: root of the worktree, a project configuration file is resolved relative to it
+	Root string
+	// NOTE: git directory of the worktree, '.git/worktrees/<name>' of a linked worktree or '.git/modules/<name>' of a submodule
+	GitDir string
+	// NOTE: directory with objects, references and configuration shared by linked worktrees, same as GitDir otherwise
+	CommonDir string
+}
+
+// Discover finds the repository of a directory by walking up to the root of its worktree.
+// A '.git' file of a linked worktree or a submodule points to its git directory,
+// and a 'commondir' file of a linked worktree points to the repository it belongs to.
+func Discover(dir string) (*Location, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving directory")
+	}
+
+	for current := dir; ; current = filepath.Dir(current) {
+		dotGit := filepath.Join(current, ".git")
+
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = readPath(dotGit, "gitdir: ", current); err != nil {
+					return nil, err
+				}
+			}
+
+			commonDir, err := readPath(filepath.Join(gitDir, "commondir"), "", gitDir)
+			if os.IsNotExist(errors.Cause(err)) {
+				commonDir = gitDir
+			} else if err != nil {
+				return nil, err
+			}
+
+			return &Location{Root: current, GitDir: gitDir, CommonDir: commonDir}, nil
+		} else if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "error reading %v", dotGit)
+		}
+
+		if filepath.Dir(current) == current {
+			return nil, errors.Errorf("not a git repository (or any of the parent directories): %v", dir)
+		}
+	}
+}
+
+// Metadata returns a filesystem of the git directory,
+// which reads shared files of a linked worktree from the common directory
+func (l *Location) Metadata() billy.Filesystem {
+	if l.CommonDir == l.GitDir {
+		return osfs.New(l.GitDir)
+	}
+
+	return dotgit.NewRepositoryFilesystem(osfs.New(l.GitDir), osfs.New(l.CommonDir))
+}
+
+// readPath reads a path of a git file, a relative path is relative to a base directory
+func readPath(file, prefix, base string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading %v", file)
+	}
+
+	line, _, _ := strings.Cut(string(content), "\n")
+	p, ok := strings.CutPrefix(strings.TrimSpace(line), prefix)
+	if !ok || p == "" {
+		return "", errors.Errorf("invalid git file %v", file)
+	}
+
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(base, p)
+	}
+
+	return filepath.Clean(p), nil
+}
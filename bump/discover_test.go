@@ -0,0 +1,174 @@
+package bump_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		// NOTE: paths are relative to a temporary directory '<dir>', a file ending with '/' is a directory
+		Files         map[string]string
+		Dir           string
+		Expected      bump.Location
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Root": {
+			Files: map[string]string{
+				"project/.git/": "",
+			},
+			Dir:      "project",
+			Expected: bump.Location{Root: "project", GitDir: "project/.git", CommonDir: "project/.git"},
+		},
+		"Subdirectory": {
+			Files: map[string]string{
+				"project/.git/":      "",
+				"project/src/tools/": "",
+			},
+			Dir:      "project/src/tools",
+			Expected: bump.Location{Root: "project", GitDir: "project/.git", CommonDir: "project/.git"},
+		},
+		"Linked Worktree": {
+			Files: map[string]string{
+				"project/.git/worktrees/feature/commondir": "../..\n",
+				"feature/.git": "gitdir: ../project/.git/worktrees/feature\n",
+				"feature/src/": "",
+			},
+			Dir:      "feature/src",
+			Expected: bump.Location{Root: "feature", GitDir: "project/.git/worktrees/feature", CommonDir: "project/.git"},
+		},
+		"Submodule": {
+			Files: map[string]string{
+				"project/.git/modules/library/": "",
+				"project/library/.git":          "gitdir: ../.git/modules/library\n",
+			},
+			Dir:      "project/library",
+			Expected: bump.Location{Root: "project/library", GitDir: "project/.git/modules/library", CommonDir: "project/.git/modules/library"},
+		},
+		"Invalid Git File": {
+			Files: map[string]string{
+				"project/.git": "invalid\n",
+			},
+			Dir:           "project",
+			ExpectedError: "invalid git file <dir>/project/.git",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir := t.TempDir()
+		for path, content := range test.Files {
+			path = filepath.Join(dir, path)
+
+			if content == "" {
+				if err := os.MkdirAll(path, 0755); err != nil {
+					t.Fatalf("error preparing test case: error creating %v: %v", path, err)
+				}
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("error preparing test case: error creating %v: %v", path, err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("error preparing test case: error writing %v: %v", path, err)
+			}
+		}
+
+		location, err := bump.Discover(filepath.Join(dir, test.Dir))
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, strings.ReplaceAll(test.ExpectedError, "<dir>", dir))
+			continue
+		}
+
+		a.Equal(bump.Location{
+			Root:      filepath.Join(dir, test.Expected.Root),
+			GitDir:    filepath.Join(dir, test.Expected.GitDir),
+			CommonDir: filepath.Join(dir, test.Expected.CommonDir),
+		}, *location)
+	}
+}
+
+func TestLinkedWorktree(t *testing.T) {
+	a := assert.New(t)
+
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main")
+	feature := filepath.Join(dir, "feature")
+
+	repo, err := git.PlainInit(main, false)
+	if err != nil {
+		t.Fatalf("error preparing test case: error initializing repository: %v", err)
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))); err != nil {
+		t.Fatalf("error preparing test case: error setting HEAD: %v", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	s := &object.Signature{Name: username, Email: email, When: time.Now()}
+	head, err := worktree.Commit("initial", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+	if err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	// NOTE: a linked worktree as created by 'git worktree add ../feature -b feature'
+	files := map[string]string{
+		filepath.Join(main, ".git", "worktrees", "feature", "commondir"): "../..\n",
+		filepath.Join(main, ".git", "worktrees", "feature", "gitdir"):    filepath.Join(feature, ".git") + "\n",
+		filepath.Join(main, ".git", "worktrees", "feature", "HEAD"):      "ref: refs/heads/feature\n",
+		filepath.Join(feature, ".git"):                                   "gitdir: " + filepath.Join(main, ".git", "worktrees", "feature") + "\n",
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("error preparing test case: error creating %v: %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing %v: %v", path, err)
+		}
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), head)); err != nil {
+		t.Fatalf("error preparing test case: error creating branch: %v", err)
+	}
+
+	location, err := bump.Discover(feature)
+	if err != nil {
+		t.Fatalf("error discovering repository: %v", err)
+	}
+
+	b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), location.Root), location.Metadata(), osfs.New(location.Root), ".")
+	if err != nil {
+		t.Fatalf("error opening repository: %v", err)
+	}
+
+	branch, err := b.Git.Branch()
+	a.Equal(nil, err)
+	a.Equal("feature", branch)
+
+	ref, err := b.Git.Repository.Head()
+	a.Equal(nil, err)
+	a.Equal(head, ref.Hash())
+}
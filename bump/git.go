@@ -2,31 +2,103 @@ package bump
 
 import (
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 	"time"
 
+	"version-bump/console"
+
+	semver "github.com/Masterminds/semver/v3"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/pkg/errors"
 )
 
-func (g *GitConfig) Save(files []string, version string) error {
-	tm := time.Now()
-	sign := &object.Signature{
-		Name:  g.UserName,
-		Email: g.UserEmail,
-		When:  tm,
-	}
+// TagError reports a release tag that can not be created safely
+type TagError struct {
+	Tag    string
+	Reason string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("refusing to create tag %v: %v", e.Tag, e.Reason)
+}
 
-	hash, err := Commit(files, version, sign, g.Worktree)
+// verifyTag ensures that a release tag of a version does not exist yet
+// and is greater than every release tag of the same major version.
+// When line is set, a version is only compared to the release tags of the same minor version,
+// since a release line is maintained after newer minor releases.
+// When head is set, it also ensures that HEAD is not released already.
+func (g *GitConfig) verifyTag(version *semver.Version, head, line bool) error {
+	name := g.tagName(version.String())
+
+	targets, err := g.releaseTags()
 	if err != nil {
 		return err
 	}
 
-	_, err = g.Repository.CreateTag(fmt.Sprintf("v%v", version), hash, &git.CreateTagOptions{
-		Tagger:  sign,
-		Message: version,
-	})
+	tags := make(map[string]plumbing.Hash)
+	names := make([]string, 0)
+	for hash, t := range targets {
+		for _, n := range t {
+			tags[n] = hash
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	if _, ok := tags[name]; ok {
+		return &TagError{Tag: name, Reason: "tag already exists"}
+	}
+
+	for _, n := range names {
+		v := g.parseTag(n)
+		if line && v.Minor() != version.Minor() {
+			continue
+		}
+
+		if v.Major() == version.Major() && !version.GreaterThan(v) {
+			return &TagError{Tag: name, Reason: fmt.Sprintf("version is not greater than existing tag %v", n)}
+		}
+	}
+
+	if !head {
+		return nil
+	}
+
+	ref, err := g.Repository.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		// NOTE: repository without commits
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "error resolving HEAD")
+	}
+
+	for _, n := range names {
+		if tags[n] == ref.Hash() {
+			return &TagError{Tag: name, Reason: fmt.Sprintf("HEAD is already tagged as %v", n)}
+		}
+	}
+
+	return nil
+}
+
+// Tag creates an annotated release tag on a commit, signed when tag signing is enabled.
+// A tag without a message is created as a lightweight tag, which can not be signed.
+func (g *GitConfig) Tag(name, message string, hash plumbing.Hash, tagger *object.Signature) error {
+	var opts *git.CreateTagOptions
+	if message != "" {
+		var err error
+		opts, err = g.tagOptions(name, message, hash, tagger)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := g.Repository.CreateTag(name, hash, opts)
 	if err != nil {
 		return errors.Wrap(err, "error tagging changes")
 	}
@@ -34,22 +106,266 @@ func (g *GitConfig) Save(files []string, version string) error {
 	return nil
 }
 
-func Commit(files []string, version string, sign *object.Signature, worktree Worktree) (plumbing.Hash, error) {
+// TagHead tags HEAD with a release tag, optionally on top of a new empty commit.
+// It returns the tagged commit.
+func (g *GitConfig) TagHead(commitMessage, tag, tagMessage string, emptyCommit bool) (plumbing.Hash, error) {
+	if err := g.verifyIdentity(); err != nil {
+		return plumbing.Hash{}, err
+	}
+
+	author, committer := g.signatures()
+
+	head, err := g.Repository.Head()
+	if err != nil {
+		return plumbing.Hash{}, errors.Wrap(err, "error resolving HEAD")
+	}
+	hash := head.Hash()
+
+	if emptyCommit {
+		opts, err := g.commitOptions(author, committer)
+		if err != nil {
+			return plumbing.Hash{}, err
+		}
+		opts.AllowEmptyCommits = true
+
+		hash, err = g.Worktree.Commit(commitMessage, opts)
+		if err != nil {
+			return plumbing.Hash{}, errors.Wrap(err, "error committing changes")
+		}
+	}
+
+	return hash, g.Tag(tag, tagMessage, hash, committer)
+}
+
+// signatures returns the author and the committer signatures of a release, the committer also tags it
+func (g *GitConfig) signatures() (*object.Signature, *object.Signature) {
+	now := time.Now()
+
+	author := &object.Signature{
+		Name:  g.Author.Name,
+		Email: g.Author.Email,
+		When:  now,
+	}
+
+	committer := &object.Signature{
+		Name:  g.Committer.Name,
+		Email: g.Committer.Email,
+		When:  now,
+	}
+
+	return author, committer
+}
+
+// Dirty returns the paths of tracked files with staged or unstaged changes
+func (g *GitConfig) Dirty() ([]string, error) {
+	status, err := g.Worktree.Status()
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving worktree status")
+	}
+
+	paths := make([]string, 0)
+	for path, s := range status {
+		if s.Staging == git.Untracked || (s.Staging == git.Unmodified && s.Worktree == git.Unmodified) {
+			continue
+		}
+
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// Commit commits exactly the provided files.
+// Staged changes of any other file are unstaged beforehand, leaving their content in the worktree,
+// and are staged again when staging or committing fails.
+func Commit(files []string, message string, opts *git.CommitOptions, worktree Worktree) (plumbing.Hash, error) {
+	status, err := worktree.Status()
+	if err != nil {
+		return plumbing.Hash{}, errors.Wrap(err, "error retrieving worktree status")
+	}
+
+	touched := make(map[string]bool)
+	for _, f := range files {
+		touched[path.Clean(f)] = true
+	}
+
+	unrelated := make([]string, 0)
+	for p, s := range status {
+		if !touched[p] && s.Staging != git.Unmodified && s.Staging != git.Untracked {
+			unrelated = append(unrelated, p)
+		}
+	}
+
+	if len(unrelated) > 0 {
+		sort.Strings(unrelated)
+		if err := worktree.Restore(&git.RestoreOptions{Staged: true, Files: unrelated}); err != nil {
+			return plumbing.Hash{}, errors.Wrap(err, "error unstaging unrelated changes")
+		}
+	}
+
 	for _, f := range files {
 		_, err := worktree.Add(f)
 		if err != nil {
+			restage(worktree, unrelated)
 			return plumbing.Hash{}, errors.Wrapf(err, "error staging a file %v", f)
 		}
 	}
 
-	hash, err := worktree.Commit(version, &git.CommitOptions{
-		All:       true,
-		Author:    sign,
-		Committer: sign,
-	})
+	hash, err := worktree.Commit(message, opts)
 	if err != nil {
+		restage(worktree, unrelated)
 		return plumbing.Hash{}, errors.Wrap(err, "error committing changes")
 	}
 
 	return hash, nil
 }
+
+// restage stages unrelated changes that were unstaged for a release commit again.
+// NOTE: a partially staged file is staged in full, since the index only records its content as a whole.
+func restage(worktree Worktree, paths []string) {
+	for _, p := range paths {
+		if _, err := worktree.Add(p); err != nil {
+			console.Error(fmt.Sprintf("    error restaging a file %v: %v", p, err))
+		}
+	}
+}
+
+// LatestTag returns the name and the version of the highest release tag
+func (g *GitConfig) LatestTag() (string, *semver.Version, error) {
+	tags, err := g.Repository.Tags()
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error listing tags")
+	}
+
+	var name string
+	var latest *semver.Version
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		if v := g.parseTag(ref.Name().Short()); v != nil && (latest == nil || v.GreaterThan(latest)) {
+			name = ref.Name().Short()
+			latest = v
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error listing tags")
+	}
+
+	return name, latest, nil
+}
+
+// tagName returns the name of a release tag of a version
+func (g *GitConfig) tagName(version string) string {
+	if g.Tags == nil {
+		return fmt.Sprintf("v%v", version)
+	}
+
+	return g.Tags.Prefix + version + g.Tags.Suffix
+}
+
+// parseTag returns the version of a release tag or nil for any other tag
+func (g *GitConfig) parseTag(name string) *semver.Version {
+	prefix, suffix := "v", ""
+	if g.Tags != nil {
+		prefix, suffix = g.Tags.Prefix, g.Tags.Suffix
+	}
+
+	if len(name) <= len(prefix)+len(suffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return nil
+	}
+
+	v, err := semver.StrictNewVersion(name[len(prefix) : len(name)-len(suffix)])
+	if err != nil {
+		return nil
+	}
+
+	return v
+}
+
+// ReachableTag returns the name and the version of the highest release tag reachable from HEAD
+func (g *GitConfig) ReachableTag() (string, *semver.Version, error) {
+	head, err := g.Repository.Head()
+	if err == plumbing.ErrReferenceNotFound {
+		// NOTE: repository without commits
+		return "", nil, nil
+	} else if err != nil {
+		return "", nil, errors.Wrap(err, "error resolving HEAD")
+	}
+
+	return g.reachableTag(head.Hash())
+}
+
+// reachableTag returns the name and the version of the highest release tag reachable from a commit
+func (g *GitConfig) reachableTag(from plumbing.Hash) (string, *semver.Version, error) {
+	targets, err := g.releaseTags()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(targets) == 0 {
+		return "", nil, nil
+	}
+
+	commits, err := g.Repository.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error reading commit history")
+	}
+
+	var name string
+	var latest *semver.Version
+	err = commits.ForEach(func(c *object.Commit) error {
+		for _, tag := range targets[c.Hash] {
+			if v := g.parseTag(tag); latest == nil || v.GreaterThan(latest) {
+				name = tag
+				latest = v
+			}
+		}
+
+		delete(targets, c.Hash)
+		if len(targets) == 0 {
+			return storer.ErrStop
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error reading commit history")
+	}
+
+	return name, latest, nil
+}
+
+// releaseTags maps commits to the names of release tags pointing at them
+func (g *GitConfig) releaseTags() (map[plumbing.Hash][]string, error) {
+	res := make(map[plumbing.Hash][]string)
+
+	tags, err := g.Repository.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing tags")
+	}
+
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		if g.parseTag(ref.Name().Short()) == nil {
+			return nil
+		}
+
+		hash := ref.Hash()
+
+		// NOTE: annotated tags point at a tag object instead of a commit
+		tag, err := g.Repository.TagObject(ref.Hash())
+		if err == nil {
+			hash = tag.Target
+		} else if err != plumbing.ErrObjectNotFound {
+			return err
+		}
+
+		res[hash] = append(res[hash], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing tags")
+	}
+
+	return res, nil
+}
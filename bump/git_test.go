@@ -1,16 +1,20 @@
 package bump_test
 
 import (
-	"fmt"
 	"testing"
 	"time"
 	"version-bump/bump"
 	"version-bump/mocks"
 
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,54 +24,82 @@ const (
 	email    string = "username@domain.com"
 )
 
-func TestSave(t *testing.T) {
+var identity = bump.Identity{Name: username, Email: email}
+
+// repository initializes an in-memory repository with a single commit tagged by the provided tags
+func repository(t *testing.T, tags ...string) *git.Repository {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("error preparing test case: error initializing repository: %v", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	s := &object.Signature{
+		Name:  username,
+		Email: email,
+		When:  time.Now(),
+	}
+
+	hash, err := worktree.Commit("initial", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            s,
+		Committer:         s,
+	})
+	if err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	for _, tag := range tags {
+		if _, err := repo.CreateTag(tag, hash, nil); err != nil {
+			t.Fatalf("error preparing test case: error creating tag %v: %v", tag, err)
+		}
+	}
+
+	return repo
+}
+
+// untagged sets up a repository mock without commits and tags
+func untagged(m *mocks.Repository) {
+	m.On("Head").Return(nil, plumbing.ErrReferenceNotFound).Maybe()
+	m.On("Tags").Return(func() (storer.ReferenceIter, error) {
+		return storer.NewReferenceSliceIter(nil), nil
+	}).Maybe()
+}
+
+// clean sets up a worktree mock without uncommitted changes
+func clean(m *mocks.Worktree) {
+	m.On("Status").Return(git.Status{}, nil).Maybe()
+}
+
+func TestTag(t *testing.T) {
 	a := assert.New(t)
 
 	type test struct {
-		Version            string
-		Files              []string
-		MockWorktreeError  error
-		MockCommitOutput   plumbing.Hash
-		MockCommitError    error
+		Message            string
 		MockCreateTagError error
+		ExpectedAnnotated  bool
 		ExpectedError      string
 	}
 
 	suite := map[string]test{
-		"Success": {
-			Version: "1.0.0",
-			Files: []string{
-				"file-1.txt",
-				"file-2.txt",
-			},
-			MockWorktreeError:  nil,
-			MockCommitOutput:   plumbing.NewHash("abc"),
-			MockCommitError:    nil,
-			MockCreateTagError: nil,
-			ExpectedError:      "",
+		"Annotated Tag": {
+			Message:           "1.0.0",
+			ExpectedAnnotated: true,
+		},
+		"Lightweight Tag": {
+			Message:           "",
+			ExpectedAnnotated: false,
 		},
 		"Error Tagging Commit": {
-			Version: "1.0.0",
-			Files: []string{
-				"file.txt",
-			},
-			MockWorktreeError:  nil,
-			MockCommitOutput:   plumbing.NewHash("abc"),
-			MockCommitError:    nil,
+			Message:            "1.0.0",
 			MockCreateTagError: errors.New("reason"),
+			ExpectedAnnotated:  true,
 			ExpectedError:      "error tagging changes: reason",
 		},
-		"Error Committing Changes": {
-			Version: "1.0.0",
-			Files: []string{
-				"file.txt",
-			},
-			MockWorktreeError:  nil,
-			MockCommitOutput:   plumbing.NewHash("abc"),
-			MockCommitError:    errors.New("reason"),
-			MockCreateTagError: nil,
-			ExpectedError:      "error committing changes: reason",
-		},
 	}
 
 	var counter int
@@ -75,28 +107,27 @@ func TestSave(t *testing.T) {
 		counter++
 		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
 
-		m1 := new(mocks.Repository)
-		m2 := new(mocks.Worktree)
+		m := new(mocks.Repository)
+		hash := plumbing.NewHash("abc")
 
-		for _, f := range test.Files {
-			m2.On("Add", f).Return(nil, nil).Once()
-		}
-
-		m2.On("Commit", test.Version, mock.AnythingOfType("*git.CommitOptions")).Return(test.MockCommitOutput, test.MockCommitError).Once()
-
-		m1.On("CreateTag", fmt.Sprintf("v%v", test.Version), test.MockCommitOutput, mock.AnythingOfType("*git.CreateTagOptions")).Return(nil, test.MockCreateTagError).Once()
+		opts := mock.MatchedBy(func(opts *git.CreateTagOptions) bool {
+			return (opts != nil) == test.ExpectedAnnotated && (opts == nil || opts.Message == test.Message)
+		})
+		m.On("CreateTag", "v1.0.0", hash, opts).Return(nil, test.MockCreateTagError).Once()
 
 		receiver := &bump.GitConfig{
-			UserName:   username,
-			UserEmail:  email,
-			Repository: m1,
-			Worktree:   m2,
+			Author:     identity,
+			Committer:  identity,
+			Repository: m,
 		}
 
-		err := receiver.Save(test.Files, test.Version)
+		tagger := &object.Signature{Name: username, Email: email, When: time.Now()}
+		err := receiver.Tag("v1.0.0", test.Message, hash, tagger)
 		if test.ExpectedError != "" || err != nil {
 			a.EqualError(err, test.ExpectedError)
 		}
+
+		m.AssertExpectations(t)
 	}
 }
 
@@ -106,9 +137,12 @@ func TestCommit(t *testing.T) {
 	type test struct {
 		Version         string
 		Files           []string
+		MockStatus      git.Status
 		MockAddError    error
 		MockCommitHash  string
 		MockCommitError error
+		ExpectedRestore []string
+		ExpectedRestage []string
 		ExpectedError   string
 	}
 
@@ -124,6 +158,24 @@ func TestCommit(t *testing.T) {
 			MockCommitError: nil,
 			ExpectedError:   "",
 		},
+		"Unrelated Staged Changes": {
+			Version: "1.0.0",
+			Files: []string{
+				"./file-1.txt",
+			},
+			MockStatus: git.Status{
+				"file-1.txt": &git.FileStatus{Staging: git.Unmodified, Worktree: git.Modified},
+				"file-2.txt": &git.FileStatus{Staging: git.Modified, Worktree: git.Unmodified},
+				"file-3.txt": &git.FileStatus{Staging: git.Added, Worktree: git.Unmodified},
+				"file-4.txt": &git.FileStatus{Staging: git.Unmodified, Worktree: git.Modified},
+				"file-5.txt": &git.FileStatus{Staging: git.Untracked, Worktree: git.Untracked},
+			},
+			MockAddError:    nil,
+			MockCommitHash:  "abc",
+			MockCommitError: nil,
+			ExpectedRestore: []string{"file-2.txt", "file-3.txt"},
+			ExpectedError:   "",
+		},
 		"Stage Error": {
 			Version: "1.0.0",
 			Files: []string{
@@ -144,6 +196,22 @@ func TestCommit(t *testing.T) {
 			MockCommitError: errors.New("reason"),
 			ExpectedError:   "error committing changes: reason",
 		},
+		"Commit Error with Unrelated Staged Changes": {
+			Version: "1.0.0",
+			Files: []string{
+				"file-1.txt",
+			},
+			MockStatus: git.Status{
+				"file-2.txt": &git.FileStatus{Staging: git.Modified, Worktree: git.Unmodified},
+				"file-3.txt": &git.FileStatus{Staging: git.Deleted, Worktree: git.Unmodified},
+			},
+			MockAddError:    nil,
+			MockCommitHash:  "abc",
+			MockCommitError: errors.New("reason"),
+			ExpectedRestore: []string{"file-2.txt", "file-3.txt"},
+			ExpectedRestage: []string{"file-2.txt", "file-3.txt"},
+			ExpectedError:   "error committing changes: reason",
+		},
 	}
 
 	var counter int
@@ -158,23 +226,394 @@ func TestCommit(t *testing.T) {
 		}
 
 		m := new(mocks.Worktree)
+		m.On("Status").Return(test.MockStatus, nil).Once()
+
+		if test.ExpectedRestore != nil {
+			m.On("Restore", &git.RestoreOptions{Staged: true, Files: test.ExpectedRestore}).Return(nil).Once()
+		}
 
 		for _, f := range test.Files {
 			m.On("Add", f).Return(nil, test.MockAddError).Once()
 		}
 
-		m.On("Commit", test.Version, &git.CommitOptions{
-			All:       true,
+		for _, f := range test.ExpectedRestage {
+			m.On("Add", f).Return(nil, nil).Once()
+		}
+
+		opts := &git.CommitOptions{
 			Author:    s,
 			Committer: s,
-		}).Return(plumbing.NewHash(test.MockCommitHash), test.MockCommitError).Once()
+		}
+
+		if test.MockAddError == nil {
+			m.On("Commit", test.Version, opts).Return(plumbing.NewHash(test.MockCommitHash), test.MockCommitError).Once()
+		}
 
-		h, err := bump.Commit(test.Files, test.Version, s, m)
+		h, err := bump.Commit(test.Files, test.Version, opts, m)
 		if test.ExpectedError != "" || err != nil {
 			a.EqualError(err, test.ExpectedError)
 			a.Equal(plumbing.NewHash(""), h)
 		} else {
 			a.Equal(plumbing.NewHash(test.MockCommitHash), h)
 		}
+
+		m.AssertExpectations(t)
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Tags            []string
+		ExpectedTag     string
+		ExpectedVersion string
+	}
+
+	suite := map[string]test{
+		"No Tags": {
+			Tags:            []string{},
+			ExpectedTag:     "",
+			ExpectedVersion: "",
+		},
+		"Highest Version": {
+			Tags:            []string{"v1.2.3", "v1.10.0", "v1.9.9"},
+			ExpectedTag:     "v1.10.0",
+			ExpectedVersion: "1.10.0",
+		},
+		"Ignore Other Tags": {
+			Tags:            []string{"v1.2.3", "latest", "v2", "2.0.0"},
+			ExpectedTag:     "v1.2.3",
+			ExpectedVersion: "1.2.3",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		receiver := &bump.GitConfig{
+			Repository: repository(t, test.Tags...),
+		}
+
+		tag, version, err := receiver.LatestTag()
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedTag, tag)
+		if test.ExpectedVersion == "" {
+			a.Nil(version)
+		} else {
+			a.Equal(test.ExpectedVersion, version.String())
+		}
+	}
+}
+
+func TestReachableTag(t *testing.T) {
+	a := assert.New(t)
+
+	repo := repository(t, "v1.0.0")
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	s := &object.Signature{
+		Name:  username,
+		Email: email,
+		When:  time.Now(),
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("error preparing test case: error resolving HEAD: %v", err)
+	}
+
+	// NOTE: a tag of a commit that is not an ancestor of HEAD
+	unreachable, err := worktree.Commit("unreachable", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+	if err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	if _, err := repo.CreateTag("v2.0.0", unreachable, nil); err != nil {
+		t.Fatalf("error preparing test case: error creating tag: %v", err)
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), head.Hash())); err != nil {
+		t.Fatalf("error preparing test case: error resetting HEAD: %v", err)
+	}
+
+	reachable, err := worktree.Commit("reachable", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+	if err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	if _, err := repo.CreateTag("v1.1.0", reachable, &git.CreateTagOptions{Tagger: s, Message: "1.1.0"}); err != nil {
+		t.Fatalf("error preparing test case: error creating tag: %v", err)
+	}
+
+	receiver := &bump.GitConfig{
+		Repository: repo,
+	}
+
+	tag, version, err := receiver.ReachableTag()
+	a.Equal(nil, err)
+	a.Equal("v1.1.0", tag)
+	a.Equal("1.1.0", version.String())
+}
+
+func TestTagHead(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Changes         bool
+		EmptyCommit     bool
+		ExpectedCommits int
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Tag HEAD": {
+			Changes:         true,
+			EmptyCommit:     false,
+			ExpectedCommits: 2,
+		},
+		"Empty Commit": {
+			EmptyCommit:     true,
+			ExpectedCommits: 2,
+		},
+		"HEAD Already Tagged": {
+			EmptyCommit:   false,
+			ExpectedError: "refusing to create tag v1.2.4: HEAD is already tagged as v1.2.3",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.3")
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		if test.Changes {
+			s := &object.Signature{Name: username, Email: email, When: time.Now()}
+			if _, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s}); err != nil {
+				t.Fatalf("error preparing test case: error committing: %v", err)
+			}
+		}
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   worktree,
+			},
+			Configuration: bump.Configuration{
+				Go:  bump.Language{Enabled: true, Directories: []string{"."}},
+				Git: bump.GitOptions{EmptyCommit: test.EmptyCommit},
+			},
+		}
+
+		err = r.Bump(bump.Patch)
+		if test.ExpectedError != "" {
+			a.EqualError(err, test.ExpectedError)
+
+			_, err := repo.Tag("v1.2.4")
+			a.Equal(git.ErrTagNotFound, err)
+			continue
+		}
+		a.Equal(nil, err)
+
+		head, err := repo.Head()
+		a.Equal(nil, err)
+
+		ref, err := repo.Tag("v1.2.4")
+		a.Equal(nil, err)
+
+		tag, err := repo.TagObject(ref.Hash())
+		a.Equal(nil, err)
+		a.Equal(head.Hash(), tag.Target)
+
+		commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+		a.Equal(nil, err)
+
+		var count int
+		a.Equal(nil, commits.ForEach(func(*object.Commit) error {
+			count++
+			return nil
+		}))
+		a.Equal(test.ExpectedCommits, count)
+	}
+}
+
+func TestVerifyTag(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Tags    []string
+		Version string
+		// NOTE: HEAD is the tagged commit, unless a commit is made after it
+		Tagged        bool
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Success": {
+			Tags:    []string{"v1.2.3", "latest"},
+			Version: "1.2.4",
+		},
+		"Untagged Repository": {
+			Tags:    []string{},
+			Version: "1.0.0",
+			Tagged:  true,
+		},
+		"Tag Exists": {
+			Tags:          []string{"v1.2.3", "v1.2.4"},
+			Version:       "1.2.4",
+			ExpectedError: "refusing to create tag v1.2.4: tag already exists",
+		},
+		"Lower than Existing Tag": {
+			Tags:          []string{"v1.2.3", "v1.3.0"},
+			Version:       "1.2.4",
+			ExpectedError: "refusing to create tag v1.2.4: version is not greater than existing tag v1.3.0",
+		},
+		"Lower than Existing Tag of Another Major Version": {
+			Tags:    []string{"v1.2.3", "v2.0.0"},
+			Version: "1.2.4",
+		},
+		"HEAD Already Tagged": {
+			Tags:          []string{"v1.2.3"},
+			Version:       "1.2.4",
+			Tagged:        true,
+			ExpectedError: "refusing to create tag v1.2.4: HEAD is already tagged as v1.2.3",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, test.Tags...)
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+		hash := head.Hash()
+
+		if !test.Tagged {
+			s := &object.Signature{Name: username, Email: email, When: time.Now()}
+			hash, err = worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+			if err != nil {
+				t.Fatalf("error preparing test case: error committing: %v", err)
+			}
+		}
+
+		// NOTE: the release commit is mocked, thus the tag is created on HEAD
+		m := new(mocks.Worktree)
+		clean(m)
+		m.On("Add", "main.go").Return(nil, nil).Maybe()
+		m.On("Commit", test.Version, mock.AnythingOfType("*git.CommitOptions")).Return(hash, nil).Maybe()
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   m,
+			},
+			Configuration: bump.Configuration{
+				Go: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, "main.go", []byte("package main\n\nconst Version string = \"0.0.1\"\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file: %v", err)
+		}
+
+		err = r.Set(test.Version, true)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+
+			var tagErr *bump.TagError
+			a.Equal(true, errors.As(err, &tagErr))
+			continue
+		}
+
+		_, err = repo.Tag("v" + test.Version)
+		a.Equal(nil, err)
+	}
+}
+
+func TestCommitTouchedFiles(t *testing.T) {
+	a := assert.New(t)
+
+	repo := repository(t)
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	s := &object.Signature{Name: username, Email: email, When: time.Now()}
+
+	for _, f := range []string{"main.go", "staged.txt", "unstaged.txt"} {
+		if err := util.WriteFile(worktree.Filesystem, f, []byte("initial\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file %v: %v", f, err)
+		}
+
+		if _, err := worktree.Add(f); err != nil {
+			t.Fatalf("error preparing test case: error staging file %v: %v", f, err)
+		}
 	}
+
+	if _, err := worktree.Commit("files", &git.CommitOptions{Author: s, Committer: s}); err != nil {
+		t.Fatalf("error preparing test case: error committing: %v", err)
+	}
+
+	for _, f := range []string{"main.go", "staged.txt", "unstaged.txt"} {
+		if err := util.WriteFile(worktree.Filesystem, f, []byte("changed\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file %v: %v", f, err)
+		}
+	}
+
+	if _, err := worktree.Add("staged.txt"); err != nil {
+		t.Fatalf("error preparing test case: error staging file: %v", err)
+	}
+
+	receiver := &bump.GitConfig{Repository: repo, Worktree: worktree}
+
+	dirty, err := receiver.Dirty()
+	a.Equal(nil, err)
+	a.Equal([]string{"main.go", "staged.txt", "unstaged.txt"}, dirty)
+
+	hash, err := bump.Commit([]string{"main.go"}, "1.0.0", &git.CommitOptions{Author: s, Committer: s}, worktree)
+	a.Equal(nil, err)
+
+	commit, err := repo.CommitObject(hash)
+	a.Equal(nil, err)
+
+	stats, err := commit.Stats()
+	a.Equal(nil, err)
+	a.Equal(1, len(stats))
+	a.Equal("main.go", stats[0].Name)
+
+	// NOTE: unrelated changes are kept in the worktree
+	dirty, err = receiver.Dirty()
+	a.Equal(nil, err)
+	a.Equal([]string{"staged.txt", "unstaged.txt"}, dirty)
+
+	content, err := util.ReadFile(worktree.Filesystem, "staged.txt")
+	a.Equal(nil, err)
+	a.Equal("changed\n", string(content))
 }
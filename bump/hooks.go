@@ -0,0 +1,142 @@
+package bump
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/pkg/errors"
+)
+
+// Hooks runs git hooks of a repository around release commits
+type Hooks struct {
+	// NOTE: core.hooksPath, or 'hooks' of the common git directory
+	Dir string
+	// NOTE: hooks run in the root of the worktree
+	WorkDir string
+	// NOTE: a commit message is passed to hooks in COMMIT_EDITMSG of the git directory
+	GitDir string
+	// NOTE: pre-commit and commit-msg hooks are skipped like 'git commit --no-verify' does
+	NoVerify bool
+}
+
+// hooksDir resolves a directory of git hooks like git does, a relative core.hooksPath is relative to the worktree
+func hooksDir(cfg *config.Config, gitDir, workDir string) string {
+	if dir := cfg.Section("core").Option("hooksPath"); dir != "" {
+		dir = expandHome(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+
+		return dir
+	}
+
+	commonDir, err := readPath(filepath.Join(gitDir, "commondir"), "", gitDir)
+	if err != nil {
+		commonDir = gitDir
+	}
+
+	return filepath.Join(commonDir, "hooks")
+}
+
+// path returns an executable hook, or an empty path when it does not exist
+func (h *Hooks) path(name string) string {
+	if h.Dir == "" {
+		return ""
+	}
+
+	p := filepath.Join(h.Dir, name)
+	info, err := os.Stat(p)
+	if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return ""
+	}
+
+	return p
+}
+
+// run executes a hook when it exists, its output is forwarded to stderr like git does
+func (h *Hooks) run(name string, args ...string) error {
+	p := h.path(name)
+	if p == "" {
+		return nil
+	}
+
+	cmd := exec.Command(p, args...)
+	cmd.Dir = h.WorkDir
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=:")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "%v hook failed", name)
+	}
+
+	return nil
+}
+
+// message passes a commit message through prepare-commit-msg and commit-msg hooks, which may edit it
+func (h *Hooks) message(message string) (string, error) {
+	if h.path("prepare-commit-msg") == "" && (h.NoVerify || h.path("commit-msg") == "") {
+		return message, nil
+	}
+
+	// NOTE: a message ends with a newline, like git writes it, thus hooks may append lines to it
+	file := filepath.Join(h.GitDir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(file, []byte(strings.TrimRight(message, "\n")+"\n"), 0644); err != nil {
+		return "", errors.Wrap(err, "error writing a commit message for hooks")
+	}
+
+	if err := h.run("prepare-commit-msg", file, "message"); err != nil {
+		return "", err
+	}
+
+	if !h.NoVerify {
+		if err := h.run("commit-msg", file); err != nil {
+			return "", err
+		}
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading a commit message of hooks")
+	}
+
+	if strings.TrimSpace(string(content)) == "" {
+		return "", errors.New("aborting commit due to empty commit message")
+	}
+
+	return string(content), nil
+}
+
+// hookWorktree runs git hooks around commits of a worktree, like git commit does
+type hookWorktree struct {
+	Worktree
+	hooks *Hooks
+}
+
+func (w *hookWorktree) Commit(message string, opts *git.CommitOptions) (plumbing.Hash, error) {
+	if !w.hooks.NoVerify {
+		if err := w.hooks.run("pre-commit"); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	}
+
+	message, err := w.hooks.message(message)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	hash, err := w.Worktree.Commit(message, opts)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	// NOTE: a post-commit hook can not affect the outcome of a commit
+	_ = w.hooks.run("post-commit")
+
+	return hash, nil
+}
@@ -0,0 +1,201 @@
+package bump_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	a := assert.New(t)
+
+	const (
+		log       string = "#!/bin/sh\necho \"$(basename \"$0\") $*\" | sed 's| .*/COMMIT_EDITMSG| COMMIT_EDITMSG|' >> \"$HOOK_LOG\"\n"
+		reject    string = "echo rejected >&2\nexit 1\n"
+		trailer   string = "echo \"Hooked: yes\" >> \"$1\"\n"
+		committed string = "1.2.4\nHooked: yes\n"
+	)
+
+	type test struct {
+		Backend string
+		Config  string
+		// NOTE: hooks relative to the repository, a hook ending with '!' is not executable
+		Hooks           map[string]string
+		NoVerify        bool
+		ExpectedLog     string
+		ExpectedMessage string
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"All Hooks": {
+			Backend: bump.BackendGoGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit":         log,
+				".git/hooks/prepare-commit-msg": log,
+				".git/hooks/commit-msg":         log + trailer,
+				".git/hooks/post-commit":        log,
+			},
+			ExpectedLog:     "pre-commit \nprepare-commit-msg COMMIT_EDITMSG message\ncommit-msg COMMIT_EDITMSG\npost-commit \n",
+			ExpectedMessage: committed,
+		},
+		"Hooks Path": {
+			Backend: bump.BackendGoGit,
+			Config:  "[core]\n\thooksPath = githooks\n",
+			Hooks: map[string]string{
+				".git/hooks/pre-commit": log + reject,
+				"githooks/commit-msg":   log + trailer,
+			},
+			ExpectedLog:     "commit-msg COMMIT_EDITMSG\n",
+			ExpectedMessage: committed,
+		},
+		"Not Executable": {
+			Backend: bump.BackendGoGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit!": log + reject,
+			},
+			ExpectedMessage: "1.2.4",
+		},
+		"Rejected by pre-commit": {
+			Backend: bump.BackendGoGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit": log + reject,
+			},
+			ExpectedLog:   "pre-commit \n",
+			ExpectedError: "error committing changes: error committing changes: pre-commit hook failed: exit status 1",
+		},
+		"Rejected by commit-msg": {
+			Backend: bump.BackendGoGit,
+			Hooks: map[string]string{
+				".git/hooks/commit-msg": log + reject,
+			},
+			ExpectedLog:   "commit-msg COMMIT_EDITMSG\n",
+			ExpectedError: "error committing changes: error committing changes: commit-msg hook failed: exit status 1",
+		},
+		"No Verify": {
+			Backend: bump.BackendGoGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit":         log + reject,
+				".git/hooks/prepare-commit-msg": log,
+				".git/hooks/commit-msg":         log + reject,
+				".git/hooks/post-commit":        log,
+			},
+			NoVerify:        true,
+			ExpectedLog:     "prepare-commit-msg COMMIT_EDITMSG message\npost-commit \n",
+			ExpectedMessage: "1.2.4\n",
+		},
+		"Git Backend": {
+			Backend: bump.BackendGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit":         log,
+				".git/hooks/prepare-commit-msg": log,
+				".git/hooks/commit-msg":         log + trailer,
+				".git/hooks/post-commit":        log,
+			},
+			ExpectedLog:     "pre-commit \nprepare-commit-msg COMMIT_EDITMSG message\ncommit-msg COMMIT_EDITMSG\npost-commit \n",
+			ExpectedMessage: committed,
+		},
+		"Git Backend - Rejected": {
+			Backend: bump.BackendGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit": log + reject,
+			},
+			ExpectedLog:   "pre-commit \n",
+			ExpectedError: "error committing changes: error committing changes: git commit failed: rejected",
+		},
+		"Git Backend - No Verify": {
+			Backend: bump.BackendGit,
+			Hooks: map[string]string{
+				".git/hooks/pre-commit":  log + reject,
+				".git/hooks/commit-msg":  log + reject,
+				".git/hooks/post-commit": log,
+			},
+			NoVerify:        true,
+			ExpectedLog:     "post-commit \n",
+			ExpectedMessage: "1.2.4\n",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, repo := project(t, fmt.Sprintf("backend = '%v'\n", test.Backend))
+
+		for path, content := range test.Hooks {
+			mode := os.FileMode(0755)
+			if path[len(path)-1] == '!' {
+				path = path[:len(path)-1]
+				mode = 0644
+			}
+
+			path = filepath.Join(dir, path)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("error preparing test case: error creating %v: %v", path, err)
+			}
+			if err := os.WriteFile(path, []byte(content), mode); err != nil {
+				t.Fatalf("error preparing test case: error writing %v: %v", path, err)
+			}
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, ".git", "config"), os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatalf("error preparing test case: error opening local configuration: %v", err)
+		}
+		if _, err := f.WriteString(test.Config); err != nil {
+			t.Fatalf("error preparing test case: error writing local configuration: %v", err)
+		}
+		f.Close()
+
+		logFile := filepath.Join(t.TempDir(), "hooks.log")
+		t.Setenv("HOOK_LOG", logFile)
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+		b.Git.Hooks.NoVerify = test.NoVerify
+
+		err = b.Bump(bump.Patch)
+
+		hooks, _ := os.ReadFile(logFile)
+		a.Equal(test.ExpectedLog, string(hooks))
+
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+
+			content, _ := os.ReadFile(filepath.Join(dir, "version.go"))
+			a.Contains(string(content), "1.2.3")
+
+			_, err := repo.Tag("v1.2.4")
+			a.Equal(git.ErrTagNotFound, err)
+			continue
+		}
+
+		tagged, err := repo.ResolveRevision(plumbing.Revision("v1.2.4"))
+		if err != nil {
+			t.Fatalf("error retrieving tag: %v", err)
+		}
+
+		commit, err := repo.CommitObject(*tagged)
+		if err != nil {
+			t.Fatalf("error retrieving commit: %v", err)
+		}
+
+		a.Equal(test.ExpectedMessage, commit.Message)
+	}
+}
@@ -0,0 +1,322 @@
+package bump
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/pkg/errors"
+)
+
+// identity environment variables, they override git configuration like they do for git
+const (
+	AuthorNameVariable     string = "GIT_AUTHOR_NAME"
+	AuthorEmailVariable    string = "GIT_AUTHOR_EMAIL"
+	CommitterNameVariable  string = "GIT_COMMITTER_NAME"
+	CommitterEmailVariable string = "GIT_COMMITTER_EMAIL"
+)
+
+// maxIncludeDepth limits nested includes of git configuration files
+const maxIncludeDepth int = 10
+
+// Identity is a name and an email of an author or a committer
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// identities resolves the author and the committer of release commits and tags the way git does:
+// author.* and committer.* options override user.*, and environment variables override the configuration
+func identities(cfg *config.Config) (Identity, Identity) {
+	user := Identity{
+		Name:  cfg.Section("user").Option("name"),
+		Email: cfg.Section("user").Option("email"),
+	}
+
+	if user.Email == "" {
+		user.Email = os.Getenv("EMAIL")
+	}
+
+	author := identity(user, cfg.Section("author"), AuthorNameVariable, AuthorEmailVariable)
+	committer := identity(user, cfg.Section("committer"), CommitterNameVariable, CommitterEmailVariable)
+
+	return author, committer
+}
+
+func identity(user Identity, section *config.Section, nameVariable, emailVariable string) Identity {
+	i := user
+
+	if name := section.Option("name"); name != "" {
+		i.Name = name
+	}
+	if email := section.Option("email"); email != "" {
+		i.Email = email
+	}
+
+	if name := os.Getenv(nameVariable); name != "" {
+		i.Name = name
+	}
+	if email := os.Getenv(emailVariable); email != "" {
+		i.Email = email
+	}
+
+	return i
+}
+
+// verifyIdentity ensures that release commits and tags have an author and a committer
+func (g *GitConfig) verifyIdentity() error {
+	if strings.TrimSpace(g.Author.Name) == "" || strings.TrimSpace(g.Author.Email) == "" {
+		return errors.Errorf("author identity is unknown, set user.name and user.email with git config, or %v and %v", AuthorNameVariable, AuthorEmailVariable)
+	}
+
+	if strings.TrimSpace(g.Committer.Name) == "" || strings.TrimSpace(g.Committer.Email) == "" {
+		return errors.Errorf("committer identity is unknown, set user.name and user.email with git config, or %v and %v", CommitterNameVariable, CommitterEmailVariable)
+	}
+
+	return nil
+}
+
+// configFiles returns system and global git configuration files, from the lowest precedence
+func configFiles() []string {
+	files := make([]string, 0)
+
+	if !isTrue(os.Getenv("GIT_CONFIG_NOSYSTEM")) {
+		if system := os.Getenv("GIT_CONFIG_SYSTEM"); system != "" {
+			files = append(files, system)
+		} else {
+			files = append(files, "/etc/gitconfig")
+		}
+	}
+
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		return append(files, global)
+	}
+
+	home, _ := os.UserHomeDir()
+
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	if xdg != "" {
+		files = append(files, filepath.Join(xdg, "git", "config"))
+	}
+
+	if home != "" {
+		files = append(files, filepath.Join(home, ".gitconfig"))
+	}
+
+	return files
+}
+
+// gitSettings reads the effective git configuration of a repository:
+// system, global and local configuration files along with their include and includeIf sections.
+// Like git, sections and includes are read in file order, thus a later value overrides an earlier one.
+func gitSettings(meta billy.Filesystem, branch string) (*config.Config, error) {
+	gitDir, err := filepath.Abs(meta.Root())
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving git directory")
+	}
+
+	l := &configLoader{
+		GitDir: gitDir,
+		Branch: branch,
+		Config: config.New(),
+	}
+
+	for _, file := range configFiles() {
+		if err := l.loadFile(file, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := meta.Open("config")
+	if os.IsNotExist(err) {
+		return l.Config, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error reading local git configuration")
+	}
+	defer f.Close()
+
+	if err := l.load(f, gitDir, 0); err != nil {
+		return nil, errors.Wrap(err, "error reading local git configuration")
+	}
+
+	return l.Config, nil
+}
+
+// configLoader merges git configuration files
+type configLoader struct {
+	// NOTE: conditions of includeIf sections are evaluated against the git directory and the current branch
+	GitDir string
+	Branch string
+	Config *config.Config
+}
+
+func (l *configLoader) loadFile(file string, depth int) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "error reading git configuration %v", file)
+	}
+	defer f.Close()
+
+	if err := l.load(f, filepath.Dir(file), depth); err != nil {
+		return errors.Wrapf(err, "error reading git configuration %v", file)
+	}
+
+	return nil
+}
+
+func (l *configLoader) load(r io.Reader, dir string, depth int) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: the decoder merges sections of the same name, thus every section is decoded on its own
+	// to load an included file in place of its include section
+	for _, chunk := range configSections(string(content)) {
+		cfg := config.New()
+		if err := config.NewDecoder(strings.NewReader(chunk)).Decode(cfg); err != nil {
+			return err
+		}
+
+		for _, s := range cfg.Sections {
+			for _, o := range s.Options {
+				l.Config.AddOption(s.Name, "", o.Key, o.Value)
+			}
+
+			for _, ss := range s.Subsections {
+				for _, o := range ss.Options {
+					l.Config.AddOption(s.Name, ss.Name, o.Key, o.Value)
+				}
+			}
+		}
+
+		includes := cfg.Section("include").OptionAll("path")
+		for _, ss := range cfg.Section("includeIf").Subsections {
+			if l.matches(ss.Name, dir) {
+				includes = append(includes, ss.OptionAll("path")...)
+			}
+		}
+
+		if len(includes) > 0 && depth >= maxIncludeDepth {
+			return errors.Errorf("includes are nested deeper than %v files", maxIncludeDepth)
+		}
+
+		for _, file := range includes {
+			file = expandHome(file)
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(dir, file)
+			}
+
+			if err := l.loadFile(file, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// configSections splits a git configuration file into its sections in file order,
+// a section starts at a header line unless the previous line continues a value
+func configSections(content string) []string {
+	sections := make([]string, 0)
+
+	var b strings.Builder
+	continued := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !continued && strings.HasPrefix(trimmed, "[") && b.Len() > 0 {
+			sections = append(sections, b.String())
+			b.Reset()
+		}
+
+		b.WriteString(line)
+		continued = strings.HasSuffix(trimmed, "\\")
+	}
+
+	if b.Len() > 0 {
+		sections = append(sections, b.String())
+	}
+
+	return sections
+}
+
+// matches evaluates a gitdir, gitdir/i or onbranch condition of an includeIf section
+func (l *configLoader) matches(condition, dir string) bool {
+	if pattern, ok := strings.CutPrefix(condition, "gitdir:"); ok {
+		return globMatch(gitDirPattern(pattern, dir), filepath.ToSlash(l.GitDir), false)
+	}
+
+	if pattern, ok := strings.CutPrefix(condition, "gitdir/i:"); ok {
+		return globMatch(gitDirPattern(pattern, dir), filepath.ToSlash(l.GitDir), true)
+	}
+
+	if pattern, ok := strings.CutPrefix(condition, "onbranch:"); ok {
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+
+		return l.Branch != "" && globMatch(pattern, l.Branch, false)
+	}
+
+	return false
+}
+
+// gitDirPattern expands a gitdir pattern like git does:
+// './' is relative to the including file, a relative pattern matches at any depth and a trailing '/' matches everything inside
+func gitDirPattern(pattern, dir string) string {
+	pattern = expandHome(pattern)
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	if rest, ok := strings.CutPrefix(pattern, "./"); ok {
+		pattern = path.Join(filepath.ToSlash(dir), rest)
+	} else if !strings.HasPrefix(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+
+	return pattern
+}
+
+// globMatch reports whether a path matches a wildmatch pattern, where '**' matches across directories
+func globMatch(pattern, name string, fold bool) bool {
+	var expression strings.Builder
+	if fold {
+		expression.WriteString("(?i)")
+	}
+	expression.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expression.WriteString("$")
+
+	ok, err := regexp.MatchString(expression.String(), name)
+	return err == nil && ok
+}
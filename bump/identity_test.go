@@ -0,0 +1,226 @@
+package bump_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentity(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		// NOTE: '<dir>' is replaced with a directory of configuration files
+		System            string
+		Global            string
+		Local             string
+		Included          string
+		Environment       map[string]string
+		ExpectedAuthor    bump.Identity
+		ExpectedCommitter bump.Identity
+	}
+
+	suite := map[string]test{
+		"Global": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "global@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "global@domain.com"},
+		},
+		"Local over Global over System": {
+			System:            "[user]\n\tname = System\n\temail = system@domain.com\n",
+			Global:            "[user]\n\tname = Global\n",
+			Local:             "[user]\n\temail = local@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "local@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "local@domain.com"},
+		},
+		"Author and Committer Sections": {
+			Global:            "[user]\n\tname = User\n\temail = user@domain.com\n[author]\n\tname = Author\n[committer]\n\temail = committer@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Author", Email: "user@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "User", Email: "committer@domain.com"},
+		},
+		"Environment Variables": {
+			Global: "[user]\n\tname = User\n\temail = user@domain.com\n",
+			Environment: map[string]string{
+				bump.AuthorNameVariable:     "Author",
+				bump.AuthorEmailVariable:    "author@domain.com",
+				bump.CommitterNameVariable:  "Committer",
+				bump.CommitterEmailVariable: "committer@domain.com",
+			},
+			ExpectedAuthor:    bump.Identity{Name: "Author", Email: "author@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Committer", Email: "committer@domain.com"},
+		},
+		"Email Variable": {
+			Global:            "[user]\n\tname = User\n",
+			Environment:       map[string]string{"EMAIL": "email@domain.com"},
+			ExpectedAuthor:    bump.Identity{Name: "User", Email: "email@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "User", Email: "email@domain.com"},
+		},
+		"Include": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[include]\n\tpath = included\n",
+			Included:          "[user]\n\temail = included@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "included@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "included@domain.com"},
+		},
+		"Setting after Include": {
+			Global:            "[include]\n\tpath = included\n[user]\n\tname = Global\n\temail = global@domain.com\n",
+			Included:          "[user]\n\tname = Included\n\temail = included@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "global@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "global@domain.com"},
+		},
+		"Include between Sections": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[include]\n\tpath = included\n[user]\n\temail = later@domain.com\n",
+			Included:          "[user]\n\tname = Included\n\temail = included@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Included", Email: "later@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Included", Email: "later@domain.com"},
+		},
+		"IncludeIf Git Directory": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[includeIf \"gitdir:<dir>/\"]\n\tpath = <dir>/included\n",
+			Included:          "[user]\n\temail = work@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "work@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "work@domain.com"},
+		},
+		"IncludeIf Relative Git Directory": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[includeIf \"gitdir:project/\"]\n\tpath = included\n",
+			Included:          "[user]\n\temail = work@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "work@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "work@domain.com"},
+		},
+		"IncludeIf Other Git Directory": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[includeIf \"gitdir:/elsewhere/\"]\n\tpath = included\n",
+			Included:          "[user]\n\temail = work@domain.com\n",
+			ExpectedAuthor:    bump.Identity{Name: "Global", Email: "global@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Global", Email: "global@domain.com"},
+		},
+		"IncludeIf Branch": {
+			Global:            "[user]\n\tname = Global\n\temail = global@domain.com\n[includeIf \"onbranch:ma*\"]\n\tpath = included\n",
+			Included:          "[user]\n\tname = Branch\n",
+			ExpectedAuthor:    bump.Identity{Name: "Branch", Email: "global@domain.com"},
+			ExpectedCommitter: bump.Identity{Name: "Branch", Email: "global@domain.com"},
+		},
+		"No Identity": {},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir := t.TempDir()
+		project := filepath.Join(dir, "project")
+
+		if _, err := git.PlainInit(project, false); err != nil {
+			t.Fatalf("error preparing test case: error initializing repository: %v", err)
+		}
+
+		files := map[string]string{
+			filepath.Join(dir, "system"):   test.System,
+			filepath.Join(dir, "global"):   test.Global,
+			filepath.Join(dir, "included"): test.Included,
+		}
+		for path, content := range files {
+			if err := os.WriteFile(path, []byte(strings.ReplaceAll(content, "<dir>", dir)), 0600); err != nil {
+				t.Fatalf("error preparing test case: error writing %v: %v", path, err)
+			}
+		}
+
+		f, err := os.OpenFile(filepath.Join(project, ".git", "config"), os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatalf("error preparing test case: error opening local configuration: %v", err)
+		}
+		if _, err := f.WriteString(test.Local); err != nil {
+			t.Fatalf("error preparing test case: error writing local configuration: %v", err)
+		}
+		f.Close()
+
+		t.Setenv("HOME", dir)
+		t.Setenv("GIT_CONFIG_SYSTEM", filepath.Join(dir, "system"))
+		t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(dir, "global"))
+		for _, variable := range []string{bump.AuthorNameVariable, bump.AuthorEmailVariable, bump.CommitterNameVariable, bump.CommitterEmailVariable, "EMAIL"} {
+			t.Setenv(variable, test.Environment[variable])
+		}
+
+		b, err := bump.New(afero.NewMemMapFs(), osfs.New(filepath.Join(project, ".git")), osfs.New(project), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+
+		a.Equal(test.ExpectedAuthor, b.Git.Author)
+		a.Equal(test.ExpectedCommitter, b.Git.Committer)
+	}
+}
+
+func TestUnknownIdentity(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Author        bump.Identity
+		Committer     bump.Identity
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"No Identity": {
+			ExpectedError: "author identity is unknown, set user.name and user.email with git config, or GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL",
+		},
+		"No Committer Email": {
+			Author:        identity,
+			Committer:     bump.Identity{Name: username},
+			ExpectedError: "committer identity is unknown, set user.name and user.email with git config, or GIT_COMMITTER_NAME and GIT_COMMITTER_EMAIL",
+		},
+		"Separate Author and Committer": {
+			Author:    identity,
+			Committer: bump.Identity{Name: "committer", Email: "committer@domain.com"},
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.3")
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		g := &bump.GitConfig{
+			Author:     test.Author,
+			Committer:  test.Committer,
+			Repository: repo,
+			Worktree:   worktree,
+		}
+
+		_, err = g.TagHead("1.2.4", "v1.2.4", "1.2.4", true)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		ref, err := repo.Tag("v1.2.4")
+		if err != nil {
+			t.Fatalf("error retrieving tag: %v", err)
+		}
+
+		tag, err := repo.TagObject(ref.Hash())
+		if err != nil {
+			t.Fatalf("error retrieving tag: %v", err)
+		}
+
+		commit, err := tag.Commit()
+		if err != nil {
+			t.Fatalf("error retrieving commit: %v", err)
+		}
+
+		a.Equal(test.Author, bump.Identity{Name: commit.Author.Name, Email: commit.Author.Email})
+		a.Equal(test.Committer, bump.Identity{Name: commit.Committer.Name, Email: commit.Committer.Email})
+		a.Equal(test.Committer, bump.Identity{Name: tag.Tagger.Name, Email: tag.Tagger.Email})
+	}
+}
@@ -1,8 +1,12 @@
 package bump
 
 import (
+	semver "github.com/Masterminds/semver/v3"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/spf13/afero"
 )
 
@@ -13,33 +17,100 @@ const (
 	Major   int    = 1
 )
 
+// DefaultRemote is a remote that releases are pushed to, unless configured otherwise
+const DefaultRemote string = "origin"
+
+// default branches of a release policy
+const (
+	DefaultMainBranch      string = "main"
+	DefaultReleaseBranches string = "release/*"
+)
+
+// version sources
+const (
+	SourceFiles string = "files"
+	SourceTags  string = "tags"
+)
+
 type Bump struct {
 	FS            afero.Fs
 	Git           GitConfig
 	Configuration Configuration
+	DryRun        bool
+	AllowDirty    bool
 }
 
 type GitConfig struct {
-	UserName   string
-	UserEmail  string
+	Author     Identity
+	Committer  Identity
 	Repository Repository
 	Worktree   Worktree
+	Signing    Signing
+	// NOTE: nil for the default 'v<version>' tag names
+	Tags       *TagFormat
+	References storer.ReferenceStorer
+	// NOTE: commits and tags are signed and pushed by git itself with the git backend
+	Backend string
+	Hooks   Hooks
 }
 
 type Repository interface {
 	Worktree() (*git.Worktree, error)
 	CreateTag(string, plumbing.Hash, *git.CreateTagOptions) (*plumbing.Reference, error)
+	Tags() (storer.ReferenceIter, error)
+	TagObject(plumbing.Hash) (*object.Tag, error)
+	Head() (*plumbing.Reference, error)
+	Log(*git.LogOptions) (object.CommitIter, error)
+	Remote(string) (*git.Remote, error)
+	Push(*git.PushOptions) error
+	Fetch(*git.FetchOptions) error
+	DeleteTag(string) error
+	Branch(string) (*config.Branch, error)
 }
 
 type Worktree interface {
 	Add(string) (plumbing.Hash, error)
 	Commit(string, *git.CommitOptions) (plumbing.Hash, error)
+	Status() (git.Status, error)
+	Restore(*git.RestoreOptions) error
 }
 
 type Configuration struct {
 	Docker     Language
 	Go         Language
 	JavaScript Language
+	Git        GitOptions
+}
+
+type GitOptions struct {
+	EmptyCommit bool `toml:"empty_commit"`
+	// NOTE: files are updated without a commit, or committed without a tag, for pipelines that release by themselves
+	NoCommit      bool   `toml:"no_commit"`
+	NoTag         bool   `toml:"no_tag"`
+	Source        string `toml:"version_source"`
+	Push          bool   `toml:"push"`
+	Remote        string `toml:"remote"`
+	CommitMessage string `toml:"commit_message"`
+	TagName       string `toml:"tag_name"`
+	TagMessage    string `toml:"tag_message"`
+	TagType       string `toml:"tag_type"`
+	Component     string `toml:"component"`
+	// NOTE: glob patterns of branches, any branch is allowed when empty
+	AllowedBranches []string `toml:"allowed_branches"`
+	ReleasePolicy   bool     `toml:"release_policy"`
+	MainBranch      string   `toml:"main_branch"`
+	ReleaseBranches string   `toml:"release_branches"`
+	// NOTE: release branches are created on major and minor releases
+	ReleaseBranch     bool   `toml:"release_branch"`
+	ReleaseBranchName string `toml:"release_branch_name"`
+	// NOTE: trailers of the release commit, custom ones are 'key: value' or 'key=value'
+	SignOff         bool     `toml:"signoff"`
+	VersionTrailers bool     `toml:"version_trailers"`
+	Trailers        []string `toml:"trailers"`
+	// NOTE: the current branch is verified against its upstream, fetched from the remote, before a release
+	CheckUpstream bool `toml:"check_upstream"`
+	// NOTE: 'go-git' by default, 'git' runs the git binary to commit, tag and push
+	Backend string `toml:"backend"`
 }
 
 type Language struct {
@@ -47,3 +118,16 @@ type Language struct {
 	Directories  []string
 	ExcludeFiles []string `toml:"exclude_files"`
 }
+
+type FileVersion struct {
+	Language    string          `json:"language"`
+	File        string          `json:"file"`
+	Version     *semver.Version `json:"version"`
+	Occurrences int             `json:"occurrences"`
+}
+
+type CheckResult struct {
+	Files    []FileVersion
+	Tag      string
+	Problems []string
+}
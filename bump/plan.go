@@ -0,0 +1,338 @@
+package bump
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"version-bump/console"
+	"version-bump/diff"
+	"version-bump/langs"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+)
+
+// change is a planned modification of a single file
+type change struct {
+	FileVersion
+	NewVersion *semver.Version
+	Original   string
+	Content    string
+	Mode       os.FileMode
+	// NOTE: number of replaced occurrences, which may differ from the number of identified versions
+	Occurrences int
+}
+
+// plan prepares the content of every provided file with a new version, without modifying any of them
+func (b *Bump) plan(files []FileVersion, next func(*semver.Version) *semver.Version) ([]change, error) {
+	changes := make([]change, 0, len(files))
+
+	for _, f := range files {
+		langSettings := langs.New(f.Language)
+		if langSettings == nil {
+			return nil, errors.New(fmt.Sprintf("not supported language: %v", f.Language))
+		}
+
+		content, err := readFile(b.FS, f.File)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading a file %v", f.File)
+		}
+
+		newVersion := next(f.Version)
+		newContent, occurrences, err := setVersion(content, *langSettings, f.Version.String(), newVersion.String())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error setting new version on content of a file %v", f.File)
+		}
+
+		if !substituted(content.String(), newContent, f.Version.String(), newVersion.String()) {
+			return nil, errors.Errorf("refusing to write a file %v: content changed beyond the version substitution", f.File)
+		}
+
+		changes = append(changes, change{
+			FileVersion: f,
+			NewVersion:  newVersion,
+			Original:    content.String(),
+			Content:     newContent,
+			Mode:        content.Mode,
+			Occurrences: occurrences,
+		})
+	}
+
+	return changes, nil
+}
+
+// validate ensures a plan is not empty, consistent and actually changes every file
+func validate(changes []change) error {
+	if len(changes) == 0 {
+		return errors.New("0 files updated")
+	}
+
+	versions := make(map[string][]string)
+	for _, c := range changes {
+		versions[c.Version.String()] = append(versions[c.Version.String()], c.File)
+	}
+
+	if len(versions) > 1 {
+		return inconsistentVersions(versions)
+	}
+
+	for _, c := range changes {
+		if c.Content == c.Original {
+			return errors.Errorf("version was not changed in file %v", c.File)
+		}
+	}
+
+	return nil
+}
+
+// execute prints a validated plan and then applies it as a release, or only previews it in dry-run mode
+func (b *Bump) execute(changes []change, unidentified []string, r *release) error {
+	printPlan(changes, unidentified)
+
+	if b.DryRun {
+		return b.preview(changes, r)
+	}
+
+	return b.apply(changes, r)
+}
+
+// apply writes a plan to the disk and commits the changes as a release, unless the release is nil.
+// The release tag is verified before any file is modified.
+// If writing, staging or committing fails, the files are restored to their original content.
+func (b *Bump) apply(changes []change, r *release) error {
+	if b.Configuration.Git.NoCommit {
+		r = nil
+	}
+
+	if r != nil {
+		if err := b.preflight(r, true); err != nil {
+			return err
+		}
+	}
+
+	for i, c := range changes {
+		if err := writeFile(b.FS, c.File, c.Content, c.Mode); err != nil {
+			b.rollback(changes[:i+1], false)
+			return errors.Wrapf(err, "error writing to file %v", c.File)
+		}
+	}
+
+	if r == nil {
+		return nil
+	}
+
+	// TODO: update changelog
+	console.CommittingChanges()
+
+	author, committer := b.Git.signatures()
+	opts, err := b.Git.commitOptions(author, committer)
+	if err != nil {
+		b.rollback(changes, false)
+		return errors.Wrap(err, "error committing changes")
+	}
+
+	hash, err := Commit(paths(changes), r.CommitMessage, opts, b.Git.Worktree)
+	if err != nil {
+		b.rollback(changes, true)
+		return errors.Wrap(err, "error committing changes")
+	}
+
+	if !b.Configuration.Git.NoTag {
+		if err := b.Git.Tag(r.TagName, r.TagMessage, hash, committer); err != nil {
+			return errors.Wrap(err, "error committing changes")
+		}
+	}
+
+	if err := b.branch(r, hash); err != nil {
+		return err
+	}
+
+	return b.publish(r, true)
+}
+
+// paths returns the files of a plan
+func paths(changes []change) []string {
+	res := make([]string, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, c.File)
+	}
+
+	return res
+}
+
+// remote returns the name of a remote that releases are pushed to
+func (b *Bump) remote() string {
+	if b.Configuration.Git.Remote != "" {
+		return b.Configuration.Git.Remote
+	}
+
+	return DefaultRemote
+}
+
+// publish pushes a release tag and a release branch to the remote when pushing is enabled.
+// The current branch is pushed along with the tag when branch is set.
+func (b *Bump) publish(r *release, branch bool) error {
+	if !b.Configuration.Git.Push {
+		return nil
+	}
+
+	console.PushingChanges(b.remote())
+
+	return b.Git.Push(b.remote(), b.tagOf(r), branch, r.Branch)
+}
+
+// tagOf returns a tag name of a release, or an empty name when tagging is disabled
+func (b *Bump) tagOf(r *release) string {
+	if b.Configuration.Git.NoTag {
+		return ""
+	}
+
+	return r.TagName
+}
+
+// preflight ensures that a release is safe before anything is modified
+func (b *Bump) preflight(r *release, head bool) error {
+	if err := b.checkBranch(r); err != nil {
+		return err
+	}
+
+	if err := b.Git.verifyIdentity(); err != nil {
+		return err
+	}
+
+	if !b.AllowDirty {
+		paths, err := b.Git.Dirty()
+		if err != nil {
+			return err
+		}
+
+		if len(paths) > 0 {
+			return errors.Errorf("worktree has uncommitted changes, commit or stash them, or use --allow-dirty: %v", strings.Join(paths, ", "))
+		}
+	}
+
+	if (b.Git.Signing.Commit || b.Git.Signing.Tag) && b.Git.Backend != BackendGit {
+		if _, _, err := b.Git.signers(); err != nil {
+			return errors.Wrap(err, "error preparing a signature")
+		}
+	}
+
+	// NOTE: a lightweight tag has no object to carry a signature, thus it would silently be created unsigned
+	if b.Git.Signing.Tag && !b.Configuration.Git.NoTag && b.Configuration.Git.TagType == TagLightweight {
+		return errors.New("lightweight tags can not be signed, use tag_type = 'annotated' or disable tag signing")
+	}
+
+	if r.Branch != "" {
+		if _, err := b.Git.References.Reference(plumbing.NewBranchReferenceName(r.Branch)); err == nil {
+			return errors.Errorf("release branch %v already exists", r.Branch)
+		} else if err != plumbing.ErrReferenceNotFound {
+			return errors.Wrapf(err, "error retrieving branch %v", r.Branch)
+		}
+	}
+
+	if b.Configuration.Git.Push {
+		if _, err := b.Git.Repository.Remote(b.remote()); err != nil {
+			return errors.Wrapf(err, "error retrieving remote %v", b.remote())
+		}
+	}
+
+	if b.Configuration.Git.CheckUpstream {
+		console.CheckingUpstream(b.remote())
+
+		if err := b.Git.VerifyUpstream(b.remote(), b.tagOf(r), !b.DryRun); err != nil {
+			return err
+		}
+	}
+
+	if b.Configuration.Git.NoTag {
+		return nil
+	}
+
+	line, err := b.onReleaseLine()
+	if err != nil {
+		return err
+	}
+
+	return b.Git.verifyTag(r.Version, head, line)
+}
+
+// rollback restores the original content of files, and re-stages them if they could have been staged
+func (b *Bump) rollback(changes []change, staged bool) {
+	console.RollingBack()
+
+	for _, c := range changes {
+		if err := writeFile(b.FS, c.File, c.Original, c.Mode); err != nil {
+			console.Error(fmt.Sprintf("    error restoring a file %v: %v", c.File, err))
+			continue
+		}
+
+		if staged {
+			if _, err := b.Git.Worktree.Add(c.File); err != nil {
+				console.Error(fmt.Sprintf("    error restoring a file %v: %v", c.File, err))
+			}
+		}
+	}
+}
+
+// preview prints the changes of a dry-run along with the commit and the tag of a release that would be created
+func (b *Bump) preview(changes []change, r *release) error {
+	if b.Configuration.Git.NoCommit {
+		r = nil
+	}
+
+	if r != nil {
+		if err := b.preflight(r, true); err != nil {
+			return err
+		}
+	}
+
+	console.DryRun()
+
+	for _, c := range changes {
+		console.Diff(diff.Unified(c.File, c.Original, c.Content))
+	}
+
+	if r != nil {
+		console.WouldCommit(r.CommitMessage, b.tagOf(r))
+
+		if r.Branch != "" {
+			console.WouldBranch(r.Branch)
+		}
+
+		if b.Configuration.Git.Push {
+			console.WouldPush(b.remote(), true, b.tagOf(r) != "")
+		}
+	}
+
+	return nil
+}
+
+// printPlan lists the planned version changes grouped by language
+func printPlan(changes []change, unidentified []string) {
+	for _, l := range []string{langs.Docker, langs.Go, langs.JavaScript} {
+		var printed bool
+
+		for _, c := range changes {
+			if c.Language == l {
+				if !printed {
+					console.Language(l)
+					printed = true
+				}
+
+				console.VersionUpdate(c.Version.String(), c.NewVersion.String(), c.File, c.Occurrences)
+			}
+		}
+
+		for _, u := range unidentified {
+			if u == l {
+				if !printed {
+					console.Language(l)
+				}
+
+				console.Error("    Version was not identified")
+			}
+		}
+	}
+}
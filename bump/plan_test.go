@@ -0,0 +1,201 @@
+package bump_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"version-bump/bump"
+	"version-bump/mocks"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBumpRollback(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Files           map[string]string
+		MockAddError    error
+		MockCommitError error
+		ExpectedError   string
+	}
+
+	suite := map[string]test{
+		"Inconsistent Versioning": {
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.2.3",
+				"main.go":    "package main\n\nconst Version string = \"1.3.0\"",
+			},
+			ExpectedError: "inconsistent versioning: 1.2.3 (Dockerfile); 1.3.0 (main.go)",
+		},
+		"Parse Error": {
+			Files: map[string]string{
+				"Dockerfile":   "FROM scratch\nLABEL org.opencontainers.image.version=1.2.3",
+				"package.json": `{"version": "latest"}`,
+			},
+			ExpectedError: "error parsing semantic version at file package.json: invalid semantic version",
+		},
+		"Stage Error": {
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.2.3",
+				"main.go":    "package main\n\nconst Version string = \"1.2.3\"",
+			},
+			MockAddError:  errors.New("reason"),
+			ExpectedError: "error committing changes: error staging a file Dockerfile: reason",
+		},
+		"Commit Error": {
+			Files: map[string]string{
+				"Dockerfile": "FROM scratch\nLABEL org.opencontainers.image.version=1.2.3",
+				"main.go":    "package main\n\nconst Version string = \"1.2.3\"",
+			},
+			MockCommitError: errors.New("reason"),
+			ExpectedError:   "error committing changes: error committing changes: reason",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		m1 := new(mocks.Repository)
+		m2 := new(mocks.Worktree)
+		clean(m2)
+		untagged(m1)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: m1,
+				Worktree:   m2,
+			},
+			Configuration: bump.Configuration{
+				Docker:     bump.Language{Enabled: true, Directories: []string{"."}},
+				Go:         bump.Language{Enabled: true, Directories: []string{"."}},
+				JavaScript: bump.Language{Enabled: true, Directories: []string{"."}},
+			},
+		}
+
+		for f, c := range test.Files {
+			if err := afero.WriteFile(r.FS, f, []byte(c), 0644); err != nil {
+				t.Errorf("error preparing test case: error writing file %v: %v", f, err)
+				continue
+			}
+		}
+
+		m2.On("Add", mock.AnythingOfType("string")).Return(nil, test.MockAddError)
+		m2.On("Commit", "2.0.0", mock.AnythingOfType("*git.CommitOptions")).Return(plumbing.NewHash("abc"), test.MockCommitError).Once()
+
+		err := r.Bump(bump.Major)
+		a.EqualError(err, test.ExpectedError)
+
+		for f, c := range test.Files {
+			content, err := afero.ReadFile(r.FS, f)
+			a.Equal(nil, err)
+			a.Equal(c, string(content))
+		}
+	}
+}
+
+func TestBumpSkipCommitAndTag(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Config          string
+		Modified        bool
+		ExpectedCommit  bool
+		ExpectedMessage string
+	}
+
+	suite := map[string]test{
+		"No Commit": {
+			Config: "no_commit = true\n",
+		},
+		"No Commit - Dirty Worktree": {
+			Config:   "no_commit = true\n",
+			Modified: true,
+		},
+		"No Tag": {
+			Config:          "no_tag = true\n",
+			ExpectedCommit:  true,
+			ExpectedMessage: "1.2.4",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, repo := project(t, test.Config)
+
+		if test.Modified {
+			if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+				t.Fatalf("error preparing test case: error writing README.md: %v", err)
+			}
+		}
+
+		before, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+
+		a.Equal(nil, b.Bump(bump.Patch))
+
+		content, _ := os.ReadFile(filepath.Join(dir, "version.go"))
+		a.Contains(string(content), "1.2.4")
+
+		_, err = repo.Tag("v1.2.4")
+		a.Equal(git.ErrTagNotFound, err)
+
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error retrieving HEAD: %v", err)
+		}
+
+		if !test.ExpectedCommit {
+			a.Equal(before.Hash(), head.Hash())
+			continue
+		}
+
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			t.Fatalf("error retrieving commit: %v", err)
+		}
+
+		a.NotEqual(before.Hash(), head.Hash())
+		a.Equal(test.ExpectedMessage, commit.Message)
+	}
+}
+
+func TestBumpNoTagWithoutVersionFiles(t *testing.T) {
+	a := assert.New(t)
+
+	for _, config := range []string{"no_commit = true\n", "no_tag = true\n"} {
+		dir, _ := project(t, config)
+		if err := os.WriteFile(filepath.Join(dir, ".bump"), []byte("[git]\n"+config), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing .bump: %v", err)
+		}
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+		b.AllowDirty = true
+
+		a.EqualError(b.Bump(bump.Patch), "project without version files is released by a tag, which is disabled by --no-commit or --no-tag")
+	}
+}
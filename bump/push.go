@@ -0,0 +1,206 @@
+package bump
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pkg/errors"
+)
+
+// TokenVariable is an environment variable with a token used to push over HTTP(S)
+const TokenVariable string = "BUMP_GIT_TOKEN"
+
+// Push pushes a release tag to a remote when it is not empty, along with the current branch when branch is set,
+// and a release branch when it is not empty.
+// A failure after the branch or the tag was pushed is reported as a partial push.
+func (g *GitConfig) Push(remote, tag string, branch bool, releaseBranch string) error {
+	_, auth, err := g.connect(remote)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: describes what reached the remote, to report a partial push
+	pushed := make([]string, 0)
+
+	if branch {
+		head, err := g.Repository.Head()
+		if err != nil {
+			return errors.Wrap(err, "error resolving HEAD")
+		}
+
+		if !head.Name().IsBranch() {
+			return errors.New("HEAD is detached, the release commit can not be pushed")
+		}
+
+		if err := g.push(remote, head.Name(), auth); err != nil {
+			return errors.Wrapf(err, "error pushing branch %v to remote %v", head.Name().Short(), remote)
+		}
+		pushed = append(pushed, fmt.Sprintf("branch %v", head.Name().Short()))
+	}
+
+	if tag != "" {
+		if err := g.push(remote, plumbing.NewTagReferenceName(tag), auth); err != nil {
+			if len(pushed) > 0 {
+				return errors.Wrapf(err, "%v to remote %v, but tag %v was rejected", partialPush(pushed), remote, tag)
+			}
+
+			return errors.Wrapf(err, "error pushing tag %v to remote %v", tag, remote)
+		}
+		pushed = append(pushed, fmt.Sprintf("tag %v", tag))
+	}
+
+	if releaseBranch != "" {
+		if err := g.push(remote, plumbing.NewBranchReferenceName(releaseBranch), auth); err != nil {
+			if len(pushed) > 0 {
+				return errors.Wrapf(err, "%v to remote %v, but release branch %v was rejected", partialPush(pushed), remote, releaseBranch)
+			}
+
+			return errors.Wrapf(err, "error pushing release branch %v to remote %v", releaseBranch, remote)
+		}
+	}
+
+	return nil
+}
+
+// partialPush describes the references that were pushed before a push failed
+func partialPush(pushed []string) string {
+	if len(pushed) == 1 {
+		return fmt.Sprintf("%v was pushed", pushed[0])
+	}
+
+	return fmt.Sprintf("%v were pushed", strings.Join(pushed, " and "))
+}
+
+// connect retrieves a remote along with the credentials to access it
+func (g *GitConfig) connect(remote string) (*git.Remote, transport.AuthMethod, error) {
+	r, err := g.Repository.Remote(remote)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error retrieving remote %v", remote)
+	}
+
+	if len(r.Config().URLs) == 0 {
+		return nil, nil, errors.Errorf("remote %v has no URL", remote)
+	}
+
+	// NOTE: git authenticates by itself with the git backend
+	if g.Backend == BackendGit {
+		return r, nil, nil
+	}
+
+	auth, err := authentication(r.Config().URLs[0])
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error authenticating to remote %v", remote)
+	}
+
+	return r, auth, nil
+}
+
+func (g *GitConfig) push(remote string, ref plumbing.ReferenceName, auth transport.AuthMethod) error {
+	return g.pushSpec(remote, config.RefSpec(fmt.Sprintf("%v:%v", ref, ref)), auth)
+}
+
+// pushSpec pushes a refspec, which may delete a remote reference or force it to a commit
+func (g *GitConfig) pushSpec(remote string, spec config.RefSpec, auth transport.AuthMethod) error {
+	err := g.Repository.Push(&git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{spec},
+		Auth:       auth,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+
+	return err
+}
+
+// authentication resolves credentials of a remote URL:
+// SSH agent for SSH remotes, a token or a git credential helper for HTTP(S) remotes
+func authentication(url string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		user := endpoint.User
+		if user == "" {
+			user = ssh.DefaultUsername
+		}
+
+		auth, err := ssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, errors.Wrap(err, "error connecting to SSH agent")
+		}
+
+		return auth, nil
+	case "http", "https":
+		if endpoint.Password != "" {
+			return &http.BasicAuth{Username: endpoint.User, Password: endpoint.Password}, nil
+		}
+
+		if token := os.Getenv(TokenVariable); token != "" {
+			user := endpoint.User
+			if user == "" {
+				user = "git"
+			}
+
+			return &http.BasicAuth{Username: user, Password: token}, nil
+		}
+
+		return credentialHelper(endpoint), nil
+	default:
+		return nil, nil
+	}
+}
+
+// credentialHelper asks git credential helpers for HTTP(S) credentials, without prompting a user.
+// Anonymous access is used when no credentials are available.
+func credentialHelper(endpoint *transport.Endpoint) transport.AuthMethod {
+	host := endpoint.Host
+	if endpoint.Port != 0 {
+		host = fmt.Sprintf("%v:%v", host, endpoint.Port)
+	}
+
+	input := fmt.Sprintf("protocol=%v\nhost=%v\npath=%v\n", endpoint.Protocol, host, strings.TrimPrefix(endpoint.Path, "/"))
+	if endpoint.User != "" {
+		input += fmt.Sprintf("username=%v\n", endpoint.User)
+	}
+
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input + "\n")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	auth := new(http.BasicAuth)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch key {
+		case "username":
+			auth.Username = value
+		case "password":
+			auth.Password = value
+		}
+	}
+
+	if auth.Password == "" {
+		return nil
+	}
+
+	return auth
+}
@@ -0,0 +1,156 @@
+package bump_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"version-bump/bump"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+// remote initializes a bare repository and registers it as an origin remote of a repository
+func remote(t *testing.T, repo *git.Repository) *git.Repository {
+	dir := t.TempDir()
+
+	bare, err := git.PlainInit(dir, true)
+	if err != nil {
+		t.Fatalf("error preparing test case: error initializing bare repository: %v", err)
+	}
+
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{dir}}); err != nil {
+		t.Fatalf("error preparing test case: error creating remote: %v", err)
+	}
+
+	return bare
+}
+
+func TestPush(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Remote        string
+		Branch        bool
+		ReleaseBranch string
+		RemoteTag     bool
+		// NOTE: the remote has the release branch on a different commit
+		RemoteReleaseBranch bool
+		ExpectedBranch      bool
+		ExpectedTag         bool
+		ExpectedError       string
+	}
+
+	suite := map[string]test{
+		"Branch and Tag": {
+			Remote:         "origin",
+			Branch:         true,
+			ExpectedBranch: true,
+			ExpectedTag:    true,
+		},
+		"Tag Only": {
+			Remote:         "origin",
+			Branch:         false,
+			ExpectedBranch: false,
+			ExpectedTag:    true,
+		},
+		"Tag Rejected": {
+			Remote:         "origin",
+			Branch:         true,
+			RemoteTag:      true,
+			ExpectedBranch: true,
+			ExpectedTag:    false,
+			ExpectedError:  "branch master was pushed to remote origin, but tag v1.2.4 was rejected: non-fast-forward update: refs/tags/v1.2.4",
+		},
+		"Release Branch Rejected": {
+			Remote:              "origin",
+			Branch:              true,
+			ReleaseBranch:       "release/1.2",
+			RemoteReleaseBranch: true,
+			ExpectedBranch:      true,
+			ExpectedTag:         true,
+			ExpectedError:       "branch master and tag v1.2.4 were pushed to remote origin, but release branch release/1.2 was rejected: non-fast-forward update: refs/heads/release/1.2",
+		},
+		"Missing Remote": {
+			Remote:        "upstream",
+			Branch:        true,
+			ExpectedError: "error retrieving remote upstream: remote not found",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.4")
+		bare := remote(t, repo)
+
+		if test.RemoteTag || test.RemoteReleaseBranch {
+			// NOTE: the same tag or release branch on a different commit
+			other := repository(t)
+			worktree, err := other.Worktree()
+			if err != nil {
+				t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+			}
+
+			s := &object.Signature{Name: username, Email: email, When: time.Now()}
+			hash, err := worktree.Commit("other", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+			if err != nil {
+				t.Fatalf("error preparing test case: error committing: %v", err)
+			}
+
+			if _, err := other.CreateTag("v1.2.4", hash, nil); err != nil {
+				t.Fatalf("error preparing test case: error creating tag: %v", err)
+			}
+
+			origin, err := repo.Remote("origin")
+			if err != nil {
+				t.Fatalf("error preparing test case: error retrieving remote: %v", err)
+			}
+
+			if _, err := other.CreateRemote(origin.Config()); err != nil {
+				t.Fatalf("error preparing test case: error creating remote: %v", err)
+			}
+
+			spec := config.RefSpec("refs/tags/v1.2.4:refs/tags/v1.2.4")
+			if test.RemoteReleaseBranch {
+				spec = config.RefSpec(fmt.Sprintf("refs/heads/master:refs/heads/%v", test.ReleaseBranch))
+			}
+
+			if err := other.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{spec}}); err != nil {
+				t.Fatalf("error preparing test case: error pushing: %v", err)
+			}
+		}
+
+		if test.ReleaseBranch != "" {
+			head, err := repo.Head()
+			if err != nil {
+				t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+			}
+
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(test.ReleaseBranch), head.Hash())); err != nil {
+				t.Fatalf("error preparing test case: error creating release branch: %v", err)
+			}
+		}
+
+		receiver := &bump.GitConfig{Repository: repo}
+
+		err := receiver.Push(test.Remote, "v1.2.4", test.Branch, test.ReleaseBranch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		_, err = bare.Reference(plumbing.NewBranchReferenceName("master"), false)
+		a.Equal(test.ExpectedBranch, err == nil)
+
+		head, err := repo.Head()
+		a.Equal(nil, err)
+
+		tag, err := bare.Reference(plumbing.NewTagReferenceName("v1.2.4"), false)
+		a.Equal(test.ExpectedTag, err == nil && tag.Hash() == head.Hash())
+	}
+}
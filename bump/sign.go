@@ -0,0 +1,259 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// signing formats
+const (
+	FormatOpenPGP string = "openpgp"
+	FormatSSH     string = "ssh"
+)
+
+// Signing configures signatures of release commits and tags
+type Signing struct {
+	Commit     bool
+	Tag        bool
+	Key        string
+	Format     string
+	Program    string
+	SSHProgram string
+}
+
+// signing reads signing settings of a git configuration
+func signing(cfg *config.Config) Signing {
+	return Signing{
+		Commit:     isTrue(cfg.Section("commit").Option("gpgsign")),
+		Tag:        isTrue(cfg.Section("tag").Option("gpgsign")),
+		Key:        cfg.Section("user").Option("signingkey"),
+		Format:     cfg.Section("gpg").Option("format"),
+		Program:    cfg.Section("gpg").Option("program"),
+		SSHProgram: cfg.Section("gpg").Subsection("ssh").Option("program"),
+	}
+}
+
+// isTrue interprets a git configuration boolean
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// signers returns either an OpenPGP key for go-git to sign with,
+// or a signer that delegates to gpg or ssh-keygen like git does
+func (g *GitConfig) signers() (*openpgp.Entity, git.Signer, error) {
+	switch g.Signing.Format {
+	case "", FormatOpenPGP:
+		if g.Signing.Key != "" {
+			if _, err := os.Stat(expandHome(g.Signing.Key)); err == nil {
+				key, err := readSigningKey(expandHome(g.Signing.Key))
+				return key, nil, err
+			}
+		}
+
+		key := g.Signing.Key
+		if key == "" {
+			key = fmt.Sprintf("%v <%v>", g.Committer.Name, g.Committer.Email)
+		}
+
+		program := g.Signing.Program
+		if program == "" {
+			program = "gpg"
+		}
+
+		return nil, &programSigner{Program: program, Args: []string{"--status-fd=2", "-bsau", key}}, nil
+	case FormatSSH:
+		if g.Signing.Key == "" {
+			return nil, nil, errors.New("user.signingkey is required for SSH signing")
+		}
+
+		program := g.Signing.SSHProgram
+		if program == "" {
+			program = "ssh-keygen"
+		}
+
+		return nil, &programSigner{Program: program, Args: []string{"-Y", "sign", "-n", "git", "-f"}, Key: g.Signing.Key}, nil
+	default:
+		return nil, nil, errors.Errorf("unsupported signing format: %v", g.Signing.Format)
+	}
+}
+
+// readSigningKey reads an unencrypted armored OpenPGP private key
+func readSigningKey(path string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading signing key")
+	}
+	defer f.Close()
+
+	keys, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading signing key")
+	}
+
+	for _, k := range keys {
+		if k.PrivateKey == nil {
+			continue
+		}
+
+		if k.PrivateKey.Encrypted {
+			return nil, errors.Errorf("signing key %v is encrypted, use a gpg key id instead", path)
+		}
+
+		return k, nil
+	}
+
+	return nil, errors.Errorf("signing key %v does not contain a private key", path)
+}
+
+// commitOptions returns the options of a release commit, signed when commit signing is enabled
+func (g *GitConfig) commitOptions(author, committer *object.Signature) (*git.CommitOptions, error) {
+	opts := &git.CommitOptions{
+		Author:    author,
+		Committer: committer,
+	}
+
+	if !g.Signing.Commit || g.Backend == BackendGit {
+		return opts, nil
+	}
+
+	key, signer, err := g.signers()
+	if err != nil {
+		return nil, errors.Wrap(err, "error preparing commit signature")
+	}
+	opts.SignKey = key
+	opts.Signer = signer
+
+	return opts, nil
+}
+
+// tagOptions returns the options of a release tag, signed when tag signing is enabled
+func (g *GitConfig) tagOptions(name, message string, hash plumbing.Hash, tagger *object.Signature) (*git.CreateTagOptions, error) {
+	opts := &git.CreateTagOptions{
+		Tagger:  tagger,
+		Message: message,
+	}
+
+	if !g.Signing.Tag || g.Backend == BackendGit {
+		return opts, nil
+	}
+
+	key, signer, err := g.signers()
+	if err != nil {
+		return nil, errors.Wrap(err, "error preparing tag signature")
+	}
+
+	if key != nil {
+		opts.SignKey = key
+		return opts, nil
+	}
+
+	// NOTE: go-git signs tags only with OpenPGP keys, the signature of any other signer is appended to the message like git does
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *tagger,
+		Message:    strings.TrimSpace(message) + "\n",
+		TargetType: plumbing.CommitObject,
+		Target:     hash,
+	}
+
+	encoded := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(encoded); err != nil {
+		return nil, errors.Wrap(err, "error preparing tag signature")
+	}
+
+	r, err := encoded.Reader()
+	if err != nil {
+		return nil, errors.Wrap(err, "error preparing tag signature")
+	}
+
+	signature, err := signer.Sign(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "error signing a tag")
+	}
+	opts.Message = tag.Message + string(signature)
+
+	return opts, nil
+}
+
+// programSigner signs objects with an external program reading the payload from stdin
+type programSigner struct {
+	Program string
+	Args    []string
+	// NOTE: SSH key, either a path to a key or a public key of an SSH agent
+	Key string
+}
+
+func (s *programSigner) Sign(message io.Reader) ([]byte, error) {
+	args := s.Args
+
+	if s.Key != "" {
+		key := expandHome(s.Key)
+
+		if literal, ok := strings.CutPrefix(key, "key::"); ok || strings.HasPrefix(key, "ssh-") {
+			if !ok {
+				literal = key
+			}
+
+			f, err := os.CreateTemp("", ".version-bump-key-*")
+			if err != nil {
+				return nil, err
+			}
+			defer os.Remove(f.Name())
+
+			if _, err := f.WriteString(literal + "\n"); err != nil {
+				f.Close()
+				return nil, err
+			}
+			f.Close()
+
+			key = f.Name()
+			args = append(append([]string{}, args...), key, "-U")
+		} else {
+			args = append(append([]string{}, args...), key)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(s.Program, args...)
+	cmd.Stdin = message
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Errorf("%v failed to sign the data: %v", s.Program, strings.TrimSpace(stderr.String()))
+	}
+
+	if stdout.Len() == 0 {
+		return nil, errors.Errorf("%v returned an empty signature", s.Program)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// expandHome expands a leading '~' of a path to the home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+
+	return path
+}
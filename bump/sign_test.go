@@ -0,0 +1,219 @@
+package bump_test
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"version-bump/bump"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// openPGPKey writes an armored OpenPGP private key to a file and returns the path along with the armored public key
+func openPGPKey(t *testing.T) (string, string) {
+	entity, err := openpgp.NewEntity(username, "", email, nil)
+	if err != nil {
+		t.Fatalf("error preparing test case: error generating OpenPGP key: %v", err)
+	}
+
+	var private, public strings.Builder
+
+	w, err := armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("error preparing test case: error encoding OpenPGP key: %v", err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatalf("error preparing test case: error encoding OpenPGP key: %v", err)
+	}
+	w.Close()
+
+	w, err = armor.Encode(&public, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("error preparing test case: error encoding OpenPGP key: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("error preparing test case: error encoding OpenPGP key: %v", err)
+	}
+	w.Close()
+
+	path := filepath.Join(t.TempDir(), "key.asc")
+	if err := os.WriteFile(path, []byte(private.String()), 0600); err != nil {
+		t.Fatalf("error preparing test case: error writing OpenPGP key: %v", err)
+	}
+
+	return path, public.String()
+}
+
+// sshKey generates an SSH key and returns the path of the private key
+func sshKey(t *testing.T) string {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", email, "-f", path).CombinedOutput(); err != nil {
+		t.Fatalf("error preparing test case: error generating SSH key: %v: %s", err, out)
+	}
+
+	return path
+}
+
+// verifySSH verifies an SSH signature of a payload with ssh-keygen
+func verifySSH(t *testing.T, payload, signature string) error {
+	path := filepath.Join(t.TempDir(), "payload.sig")
+	if err := os.WriteFile(path, []byte(signature), 0600); err != nil {
+		t.Fatalf("error writing signature: %v", err)
+	}
+
+	cmd := exec.Command("ssh-keygen", "-Y", "check-novalidate", "-n", "git", "-s", path)
+	cmd.Stdin = strings.NewReader(payload)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Errorf("%v: %s", err, out)
+	}
+
+	return nil
+}
+
+// release tags HEAD of a new repository on top of an empty commit with the provided signing settings
+func release(t *testing.T, signing bump.Signing) (*object.Commit, *object.Tag, error) {
+	repo := repository(t, "v1.2.3")
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+	}
+
+	g := &bump.GitConfig{
+		Author:     identity,
+		Committer:  identity,
+		Repository: repo,
+		Worktree:   worktree,
+		Signing:    signing,
+	}
+
+	if _, err := g.TagHead("1.2.4", "v1.2.4", "1.2.4", true); err != nil {
+		return nil, nil, err
+	}
+
+	ref, err := repo.Tag("v1.2.4")
+	if err != nil {
+		t.Fatalf("error retrieving tag: %v", err)
+	}
+
+	tag, err := repo.TagObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("error retrieving tag: %v", err)
+	}
+
+	commit, err := repo.CommitObject(tag.Target)
+	if err != nil {
+		t.Fatalf("error retrieving commit: %v", err)
+	}
+
+	return commit, tag, nil
+}
+
+// payload returns an encoded object without its signature
+func payload(t *testing.T, o interface {
+	EncodeWithoutSignature(plumbing.EncodedObject) error
+}) string {
+	encoded := &plumbing.MemoryObject{}
+	if err := o.EncodeWithoutSignature(encoded); err != nil {
+		t.Fatalf("error encoding object: %v", err)
+	}
+
+	r, err := encoded.Reader()
+	if err != nil {
+		t.Fatalf("error encoding object: %v", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error encoding object: %v", err)
+	}
+
+	return string(b)
+}
+
+func TestSignOpenPGP(t *testing.T) {
+	a := assert.New(t)
+
+	key, public := openPGPKey(t)
+
+	commit, tag, err := release(t, bump.Signing{Commit: true, Tag: true, Key: key, Format: bump.FormatOpenPGP})
+	a.Equal(nil, err)
+
+	_, err = commit.Verify(public)
+	a.Equal(nil, err)
+
+	_, err = tag.Verify(public)
+	a.Equal(nil, err)
+	a.Equal("1.2.4\n", tag.Message)
+}
+
+func TestSignSSH(t *testing.T) {
+	a := assert.New(t)
+
+	key := sshKey(t)
+
+	type test struct {
+		Signing       bump.Signing
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Commit and Tag": {
+			Signing: bump.Signing{Commit: true, Tag: true, Key: key, Format: bump.FormatSSH},
+		},
+		"Tag Only": {
+			Signing: bump.Signing{Commit: false, Tag: true, Key: key, Format: bump.FormatSSH},
+		},
+		"Missing Key": {
+			Signing:       bump.Signing{Commit: true, Tag: true, Format: bump.FormatSSH},
+			ExpectedError: "error preparing commit signature: user.signingkey is required for SSH signing",
+		},
+		"Unsupported Format": {
+			Signing:       bump.Signing{Commit: false, Tag: true, Key: key, Format: "x509"},
+			ExpectedError: "error preparing tag signature: unsupported signing format: x509",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		commit, tag, err := release(t, test.Signing)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		if test.Signing.Commit {
+			a.Equal(true, strings.HasPrefix(commit.PGPSignature, "-----BEGIN SSH SIGNATURE-----"))
+			a.Equal(nil, verifySSH(t, payload(t, commit), commit.PGPSignature))
+		} else {
+			a.Equal("", commit.PGPSignature)
+		}
+
+		a.Equal(true, strings.HasPrefix(tag.PGPSignature, "-----BEGIN SSH SIGNATURE-----"))
+		a.Equal("1.2.4\n", tag.Message)
+		a.Equal(nil, verifySSH(t, payload(t, tag), tag.PGPSignature))
+	}
+}
+
+func TestSignDisabled(t *testing.T) {
+	a := assert.New(t)
+
+	commit, tag, err := release(t, bump.Signing{Key: "missing", Format: "x509"})
+	a.Equal(nil, err)
+	a.Equal("", commit.PGPSignature)
+	a.Equal("", tag.PGPSignature)
+}
@@ -0,0 +1,187 @@
+package bump
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+// default templates of a release
+const (
+	DefaultCommitMessage string = "{{.Version}}"
+	DefaultTagName       string = "v{{.Version}}"
+	DefaultTagMessage    string = "{{.Version}}"
+)
+
+// tag types
+const (
+	TagAnnotated   string = "annotated"
+	TagLightweight string = "lightweight"
+)
+
+// TemplateData is available to commit message and tag message templates
+type TemplateData struct {
+	Version         string
+	PreviousVersion string
+	Level           string
+	Date            string
+	Files           []string
+	Component       string
+}
+
+// TagFormat renders and parses release tag names.
+// A tag name template may only refer to a version and a component, thus every tag name is a version surrounded by a constant prefix and suffix.
+type TagFormat struct {
+	Prefix string
+	Suffix string
+}
+
+// NewTagFormat validates a tag name template
+func NewTagFormat(text, component string) (*TagFormat, error) {
+	const placeholder string = "\x00"
+
+	tmpl, err := template.New("tag_name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing tag name template")
+	}
+
+	var b strings.Builder
+	data := struct {
+		Version   string
+		Component string
+	}{
+		Version:   placeholder,
+		Component: component,
+	}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, errors.Wrap(err, "error rendering tag name template, only .Version and .Component are available")
+	}
+
+	prefix, suffix, ok := strings.Cut(b.String(), placeholder)
+	if !ok || strings.Contains(suffix, placeholder) {
+		return nil, errors.Errorf("tag name template %q must contain a version exactly once", text)
+	}
+
+	return &TagFormat{Prefix: prefix, Suffix: suffix}, nil
+}
+
+// release is a version with its rendered commit message and tag
+type release struct {
+	Version *semver.Version
+	// NOTE: level of an explicit version is derived from the previous version
+	Level         int
+	CommitMessage string
+	TagName       string
+	// NOTE: empty for lightweight tags
+	TagMessage string
+	// NOTE: release branch to create, empty unless enabled for major and minor releases
+	Branch string
+}
+
+// newRelease renders the commit message and the tag of a version release
+func (b *Bump) newRelease(previous, version *semver.Version, level int, files []string) (*release, error) {
+	data := TemplateData{
+		Version:   version.String(),
+		Level:     levelName(level),
+		Date:      time.Now().Format("2006-01-02"),
+		Files:     files,
+		Component: b.Configuration.Git.Component,
+	}
+
+	if previous != nil {
+		data.PreviousVersion = previous.String()
+	}
+
+	commitMessage, err := render("commit_message", b.Configuration.Git.CommitMessage, DefaultCommitMessage, data)
+	if err != nil {
+		return nil, err
+	}
+
+	trailers, err := b.releaseTrailers(data)
+	if err != nil {
+		return nil, err
+	}
+	commitMessage = appendTrailers(commitMessage, trailers)
+
+	r := &release{
+		Version:       version,
+		Level:         level,
+		CommitMessage: commitMessage,
+		TagName:       b.Git.tagName(version.String()),
+	}
+
+	if level != Major && level != Minor && level != Patch {
+		r.Level = releaseLevel(previous, version)
+	}
+
+	if b.Configuration.Git.ReleaseBranch && (r.Level == Major || r.Level == Minor) {
+		r.Branch, err = b.releaseBranchName(version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch b.Configuration.Git.TagType {
+	case "", TagAnnotated:
+		r.TagMessage, err = render("tag_message", b.Configuration.Git.TagMessage, DefaultTagMessage, data)
+		if err != nil {
+			return nil, err
+		}
+	case TagLightweight:
+	default:
+		return nil, errors.Errorf("unsupported tag type: %v", b.Configuration.Git.TagType)
+	}
+
+	return r, nil
+}
+
+// releaseLevel derives the level of a release from the previous version
+func releaseLevel(previous, version *semver.Version) int {
+	if previous == nil || version.Major() != previous.Major() {
+		return Major
+	} else if version.Minor() != previous.Minor() {
+		return Minor
+	}
+
+	return Patch
+}
+
+// render executes a template, or its default when not configured
+func render(name, text, fallback string, data TemplateData) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing %v template", name)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.Wrapf(err, "error rendering %v template", name)
+	}
+
+	if strings.TrimSpace(b.String()) == "" {
+		return "", errors.Errorf("%v template rendered an empty text", name)
+	}
+
+	return b.String(), nil
+}
+
+// levelName returns the name of a bump level, or 'set' for an explicit version
+func levelName(level int) string {
+	switch level {
+	case Major:
+		return "major"
+	case Minor:
+		return "minor"
+	case Patch:
+		return "patch"
+	default:
+		return "set"
+	}
+}
@@ -0,0 +1,218 @@
+package bump_test
+
+import (
+	"testing"
+	"time"
+	"version-bump/bump"
+	"version-bump/mocks"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewTagFormat(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Template       string
+		Component      string
+		ExpectedFormat *bump.TagFormat
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Default": {
+			Template:       bump.DefaultTagName,
+			ExpectedFormat: &bump.TagFormat{Prefix: "v", Suffix: ""},
+		},
+		"Prefix": {
+			Template:       "release-{{.Version}}",
+			ExpectedFormat: &bump.TagFormat{Prefix: "release-", Suffix: ""},
+		},
+		"Component": {
+			Template:       "{{.Component}}/v{{.Version}}",
+			Component:      "server",
+			ExpectedFormat: &bump.TagFormat{Prefix: "server/v", Suffix: ""},
+		},
+		"Suffix": {
+			Template:       "v{{.Version}}-stable",
+			ExpectedFormat: &bump.TagFormat{Prefix: "v", Suffix: "-stable"},
+		},
+		"Missing Version": {
+			Template:      "latest",
+			ExpectedError: "tag name template \"latest\" must contain a version exactly once",
+		},
+		"Repeated Version": {
+			Template:      "{{.Version}}-{{.Version}}",
+			ExpectedError: "tag name template \"{{.Version}}-{{.Version}}\" must contain a version exactly once",
+		},
+		"Unavailable Field": {
+			Template:      "{{.Date}}-{{.Version}}",
+			ExpectedError: "error rendering tag name template, only .Version and .Component are available: template: tag_name:1:2: executing \"tag_name\" at <.Date>: can't evaluate field Date in type struct { Version string; Component string }",
+		},
+		"Parse Error": {
+			Template:      "v{{.Version}",
+			ExpectedError: "error parsing tag name template: template: tag_name:1: bad character U+007D '}'",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		format, err := bump.NewTagFormat(test.Template, test.Component)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+		}
+
+		a.Equal(test.ExpectedFormat, format)
+	}
+}
+
+func TestBumpTemplates(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Options               bump.GitOptions
+		Tags                  *bump.TagFormat
+		ExpectedCommitMessage string
+		ExpectedTag           string
+		ExpectedTagMessage    string
+		ExpectedError         string
+	}
+
+	suite := map[string]test{
+		"Defaults": {
+			Options:               bump.GitOptions{},
+			ExpectedCommitMessage: "1.2.4",
+			ExpectedTag:           "v1.2.4",
+			ExpectedTagMessage:    "1.2.4\n",
+		},
+		"Conventional Commit": {
+			Options: bump.GitOptions{
+				CommitMessage: "chore(release): {{.Version}}\n\nFiles: {{range .Files}}{{.}} {{end}}",
+				TagName:       "release-{{.Version}}",
+				TagMessage:    "Release {{.Version}} ({{.Level}} after {{.PreviousVersion}})",
+			},
+			Tags:                  &bump.TagFormat{Prefix: "release-"},
+			ExpectedCommitMessage: "chore(release): 1.2.4\n\nFiles: main.go ",
+			ExpectedTag:           "release-1.2.4",
+			ExpectedTagMessage:    "Release 1.2.4 (patch after 1.2.3)\n",
+		},
+		"Component": {
+			Options: bump.GitOptions{
+				CommitMessage: "release({{.Component}}): {{.Version}}",
+				TagName:       "{{.Component}}/v{{.Version}}",
+				Component:     "server",
+			},
+			Tags:                  &bump.TagFormat{Prefix: "server/v"},
+			ExpectedCommitMessage: "release(server): 1.2.4",
+			ExpectedTag:           "server/v1.2.4",
+			ExpectedTagMessage:    "1.2.4\n",
+		},
+		"Lightweight Tag": {
+			Options: bump.GitOptions{
+				TagType: bump.TagLightweight,
+			},
+			ExpectedCommitMessage: "1.2.4",
+			ExpectedTag:           "v1.2.4",
+		},
+		"Unsupported Tag Type": {
+			Options: bump.GitOptions{
+				TagType: "signed",
+			},
+			ExpectedError: "unsupported tag type: signed",
+		},
+		"Template Error": {
+			Options: bump.GitOptions{
+				CommitMessage: "{{.Author}}",
+			},
+			ExpectedError: "error rendering commit_message template: template: commit_message:1:2: executing \"commit_message\" at <.Author>: can't evaluate field Author in type bump.TemplateData",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.3")
+		m := new(mocks.Worktree)
+		clean(m)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  identity,
+				Repository: repo,
+				Worktree:   m,
+				Tags:       test.Tags,
+			},
+			Configuration: bump.Configuration{
+				Go:  bump.Language{Enabled: true, Directories: []string{"."}},
+				Git: test.Options,
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, "main.go", []byte("package main\n\nconst Version string = \"1.2.3\"\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file: %v", err)
+		}
+
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		// NOTE: the release commit is mocked, thus the tag is created on an unreleased commit
+		s := &object.Signature{Name: username, Email: email, When: time.Now()}
+		head, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+		if err != nil {
+			t.Fatalf("error preparing test case: error committing: %v", err)
+		}
+
+		if test.ExpectedError == "" {
+			m.On("Add", "main.go").Return(nil, nil).Once()
+			m.On("Commit", test.ExpectedCommitMessage, mock.AnythingOfType("*git.CommitOptions")).Return(head, nil).Once()
+		}
+
+		err = r.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		ref, err := repo.Tag(test.ExpectedTag)
+		a.Equal(nil, err)
+
+		tag, err := repo.TagObject(ref.Hash())
+		if test.ExpectedTagMessage == "" {
+			a.Equal(plumbing.ErrObjectNotFound, err)
+			a.Equal(head, ref.Hash())
+		} else {
+			a.Equal(nil, err)
+			a.Equal(test.ExpectedTagMessage, tag.Message)
+		}
+
+		m.AssertExpectations(t)
+	}
+}
+
+func TestLatestTagFormat(t *testing.T) {
+	a := assert.New(t)
+
+	receiver := &bump.GitConfig{
+		Repository: repository(t, "server/v1.2.3", "server/v1.10.0", "client/v2.0.0", "v9.9.9", "server/vlatest"),
+		Tags:       &bump.TagFormat{Prefix: "server/v"},
+	}
+
+	tag, version, err := receiver.LatestTag()
+	a.Equal(nil, err)
+	a.Equal("server/v1.10.0", tag)
+	a.Equal("1.10.0", version.String())
+}
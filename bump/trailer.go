@@ -0,0 +1,115 @@
+package bump
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// trailer keys written by version-bump
+const (
+	TrailerSignedOffBy     string = "Signed-off-by"
+	TrailerReleaseAs       string = "Release-As"
+	TrailerPreviousVersion string = "Previous-Version"
+)
+
+// trailerLine matches a 'key: value' line of a trailer block
+var trailerLine = regexp.MustCompile(`^[A-Za-z0-9-]+:\s`)
+
+// trailerKey matches a trailer key
+var trailerKey = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// parseTrailer parses a 'key: value' or a 'key=value' trailer, like 'git commit --trailer' does
+func parseTrailer(text string) (string, error) {
+	i := strings.IndexAny(text, ":=")
+	if i < 0 {
+		return "", errors.Errorf("invalid trailer %q, expected 'key: value' or 'key=value'", text)
+	}
+
+	key := strings.TrimSpace(text[:i])
+	value := strings.TrimSpace(text[i+1:])
+	if !trailerKey.MatchString(key) || value == "" || strings.ContainsAny(value, "\r\n") {
+		return "", errors.Errorf("invalid trailer %q, expected 'key: value' or 'key=value'", text)
+	}
+
+	return fmt.Sprintf("%v: %v", key, value), nil
+}
+
+// releaseTrailers returns the trailers of a release commit in the order they are written
+func (b *Bump) releaseTrailers(data TemplateData) ([]string, error) {
+	trailers := make([]string, 0)
+
+	for _, t := range b.Configuration.Git.Trailers {
+		trailer, err := parseTrailer(t)
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, trailer)
+	}
+
+	if b.Configuration.Git.VersionTrailers {
+		trailers = append(trailers, fmt.Sprintf("%v: %v", TrailerReleaseAs, data.Version))
+		if data.PreviousVersion != "" {
+			trailers = append(trailers, fmt.Sprintf("%v: %v", TrailerPreviousVersion, data.PreviousVersion))
+		}
+	}
+
+	// NOTE: a sign-off comes last, like 'git commit --signoff' adds it after other trailers,
+	// an unknown committer is refused by preflight checks of a release
+	if b.Configuration.Git.SignOff {
+		trailers = append(trailers, fmt.Sprintf("%v: %v <%v>", TrailerSignedOffBy, b.Git.Committer.Name, b.Git.Committer.Email))
+	}
+
+	return trailers, nil
+}
+
+// trailerBlock returns the trailers of the last paragraph of a commit message, a subject is never a trailer block
+func trailerBlock(message string) []string {
+	paragraphs := strings.Split(strings.TrimRight(message, "\n"), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	lines := strings.Split(paragraphs[len(paragraphs)-1], "\n")
+	for _, line := range lines {
+		if !trailerLine.MatchString(line) {
+			return nil
+		}
+	}
+
+	return lines
+}
+
+// appendTrailers adds trailers to a commit message in git's trailer block format:
+// a last paragraph of 'key: value' lines, separated from the message by a blank line.
+// An existing trailer block of a message is extended, and trailers it already has are skipped.
+func appendTrailers(message string, trailers []string) string {
+	if len(trailers) == 0 {
+		return message
+	}
+
+	message = strings.TrimRight(message, "\n")
+	existing := trailerBlock(message)
+
+	var b strings.Builder
+	b.WriteString(message)
+	if len(existing) == 0 {
+		b.WriteString("\n")
+	}
+
+	for _, trailer := range trailers {
+		if slices.Contains(existing, trailer) {
+			continue
+		}
+		existing = append(existing, trailer)
+
+		b.WriteString("\n")
+		b.WriteString(trailer)
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
@@ -0,0 +1,172 @@
+package bump_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"version-bump/bump"
+	"version-bump/mocks"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBumpTrailers(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Options               bump.GitOptions
+		Committer             bump.Identity
+		ExpectedCommitMessage string
+		ExpectedError         string
+	}
+
+	suite := map[string]test{
+		"No Trailers": {
+			Options:               bump.GitOptions{},
+			Committer:             identity,
+			ExpectedCommitMessage: "1.2.4",
+		},
+		"Sign-off": {
+			Options:               bump.GitOptions{SignOff: true},
+			Committer:             identity,
+			ExpectedCommitMessage: "1.2.4\n\nSigned-off-by: username <username@domain.com>\n",
+		},
+		"Version Trailers": {
+			Options:               bump.GitOptions{VersionTrailers: true},
+			Committer:             identity,
+			ExpectedCommitMessage: "1.2.4\n\nRelease-As: 1.2.4\nPrevious-Version: 1.2.3\n",
+		},
+		"Custom Trailers": {
+			Options: bump.GitOptions{
+				Trailers: []string{"Co-authored-by: Jane Doe <jane@domain.com>", "Ticket = ABC-123"},
+			},
+			Committer:             identity,
+			ExpectedCommitMessage: "1.2.4\n\nCo-authored-by: Jane Doe <jane@domain.com>\nTicket: ABC-123\n",
+		},
+		"All Trailers": {
+			Options: bump.GitOptions{
+				CommitMessage:   "chore(release): {{.Version}}\n\nRelease of the {{.Level}} version.\n",
+				SignOff:         true,
+				VersionTrailers: true,
+				Trailers:        []string{"Ticket: ABC-123"},
+			},
+			Committer:             identity,
+			ExpectedCommitMessage: "chore(release): 1.2.4\n\nRelease of the patch version.\n\nTicket: ABC-123\nRelease-As: 1.2.4\nPrevious-Version: 1.2.3\nSigned-off-by: username <username@domain.com>\n",
+		},
+		"Existing Trailer Block": {
+			Options: bump.GitOptions{
+				CommitMessage: "{{.Version}}\n\nTicket: ABC-123\nSigned-off-by: username <username@domain.com>",
+				SignOff:       true,
+				Trailers:      []string{"Ticket: ABC-123", "Reviewed-by: Jane Doe <jane@domain.com>"},
+			},
+			Committer:             identity,
+			ExpectedCommitMessage: "1.2.4\n\nTicket: ABC-123\nSigned-off-by: username <username@domain.com>\nReviewed-by: Jane Doe <jane@domain.com>\n",
+		},
+		"Subject Is Not a Trailer Block": {
+			Options: bump.GitOptions{
+				CommitMessage: "Release: {{.Version}}",
+				SignOff:       true,
+			},
+			Committer:             identity,
+			ExpectedCommitMessage: "Release: 1.2.4\n\nSigned-off-by: username <username@domain.com>\n",
+		},
+		"Invalid Trailer": {
+			Options: bump.GitOptions{
+				Trailers: []string{"Reviewed by: Jane Doe"},
+			},
+			Committer:     identity,
+			ExpectedError: "invalid trailer \"Reviewed by: Jane Doe\", expected 'key: value' or 'key=value'",
+		},
+		"Empty Trailer Value": {
+			Options: bump.GitOptions{
+				Trailers: []string{"Ticket:"},
+			},
+			Committer:     identity,
+			ExpectedError: "invalid trailer \"Ticket:\", expected 'key: value' or 'key=value'",
+		},
+		"Sign-off Without Identity": {
+			Options:       bump.GitOptions{SignOff: true},
+			Committer:     bump.Identity{Name: username},
+			ExpectedError: "committer identity is unknown, set user.name and user.email with git config, or GIT_COMMITTER_NAME and GIT_COMMITTER_EMAIL",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		repo := repository(t, "v1.2.3")
+		m := new(mocks.Worktree)
+		clean(m)
+
+		r := bump.Bump{
+			FS: afero.NewMemMapFs(),
+			Git: bump.GitConfig{
+				Author:     identity,
+				Committer:  test.Committer,
+				Repository: repo,
+				Worktree:   m,
+			},
+			Configuration: bump.Configuration{
+				Go:  bump.Language{Enabled: true, Directories: []string{"."}},
+				Git: test.Options,
+			},
+		}
+
+		if err := afero.WriteFile(r.FS, "main.go", []byte("package main\n\nconst Version string = \"1.2.3\"\n"), 0644); err != nil {
+			t.Fatalf("error preparing test case: error writing file: %v", err)
+		}
+
+		worktree, err := repo.Worktree()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving worktree: %v", err)
+		}
+
+		// NOTE: the release commit is mocked, thus the tag is created on an unreleased commit
+		s := &object.Signature{Name: username, Email: email, When: time.Now()}
+		head, err := worktree.Commit("changes", &git.CommitOptions{AllowEmptyCommits: true, Author: s, Committer: s})
+		if err != nil {
+			t.Fatalf("error preparing test case: error committing: %v", err)
+		}
+
+		if test.ExpectedError == "" {
+			m.On("Add", "main.go").Return(nil, nil).Once()
+			m.On("Commit", test.ExpectedCommitMessage, mock.AnythingOfType("*git.CommitOptions")).Return(head, nil).Once()
+		}
+
+		err = r.Bump(bump.Patch)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+			continue
+		}
+
+		m.AssertExpectations(t)
+	}
+}
+
+func TestSignOffWithoutIdentity(t *testing.T) {
+	a := assert.New(t)
+
+	dir, _ := project(t, "signoff = true\n")
+	for _, variable := range []string{bump.AuthorNameVariable, bump.AuthorEmailVariable, bump.CommitterNameVariable, bump.CommitterEmailVariable, "EMAIL"} {
+		t.Setenv(variable, "")
+	}
+
+	// NOTE: read-only commands do not require an identity
+	b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+	if !a.Equal(nil, err) {
+		return
+	}
+
+	current, _, err := b.Current()
+	a.Equal(nil, err)
+	a.Equal("1.2.3", current.String())
+
+	a.EqualError(b.Bump(bump.Patch), "author identity is unknown, set user.name and user.email with git config, or GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL")
+}
@@ -0,0 +1,406 @@
+package bump
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"version-bump/console"
+
+	semver "github.com/Masterminds/semver/v3"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// undo is a release commit at HEAD to revert
+type undo struct {
+	Commit *object.Commit
+	Parent *object.Commit
+	// NOTE: files changed by the release commit
+	Changes object.Changes
+	Version *semver.Version
+	Branch  string
+	// NOTE: empty when the release commit is not tagged
+	Tag string
+	// NOTE: empty unless a release branch points at the release commit
+	ReleaseBranch string
+}
+
+// Undo reverts the release commit at HEAD: the files it changed are restored, the current branch is reset to its parent,
+// and its release tag and release branch are deleted, while other changes of the worktree and the index are preserved.
+// A release that was pushed is refused, unless remote is set, in which case it is reverted on the remote as well.
+func (b *Bump) Undo(remote bool) error {
+	u, err := b.undoPlan()
+	if err != nil {
+		return err
+	}
+
+	console.UndoingRelease(u.Version.String(), u.Commit.Hash.String()[:7])
+
+	status, err := b.Git.Worktree.Status()
+	if err != nil {
+		return errors.Wrap(err, "error retrieving worktree status")
+	}
+
+	for _, p := range changedPaths(u.Changes) {
+		if s, ok := status[p]; ok && (s.Staging != git.Unmodified || s.Worktree != git.Unmodified) {
+			return errors.Errorf("file %v was modified after the release, commit or stash the changes before undoing it", p)
+		}
+	}
+
+	if err := b.undoRemote(u, remote); err != nil {
+		return err
+	}
+
+	for _, c := range u.Changes {
+		if err := b.restore(c, u.Parent); err != nil {
+			return err
+		}
+	}
+
+	console.ResettingBranch(u.Branch, u.Parent.Hash.String()[:7])
+
+	if err := b.Git.References.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(u.Branch), u.Parent.Hash)); err != nil {
+		return errors.Wrapf(err, "error resetting branch %v", u.Branch)
+	}
+
+	for _, p := range changedPaths(u.Changes) {
+		if _, err := b.Git.Worktree.Add(p); err != nil {
+			return errors.Wrapf(err, "error staging a file %v", p)
+		}
+	}
+
+	if u.Tag != "" {
+		console.DeletingTag(u.Tag)
+
+		if err := b.Git.Repository.DeleteTag(u.Tag); err != nil {
+			return errors.Wrapf(err, "error deleting tag %v", u.Tag)
+		}
+	}
+
+	if u.ReleaseBranch != "" {
+		console.DeletingBranch(u.ReleaseBranch)
+
+		if err := b.Git.References.RemoveReference(plumbing.NewBranchReferenceName(u.ReleaseBranch)); err != nil {
+			return errors.Wrapf(err, "error deleting release branch %v", u.ReleaseBranch)
+		}
+	}
+
+	return nil
+}
+
+// undoPlan ensures that HEAD is a release commit of version-bump, identified by a Release-As trailer,
+// or by a release tag along with a commit message matching the commit message template
+func (b *Bump) undoPlan() (*undo, error) {
+	branch, err := b.Git.Branch()
+	if err != nil {
+		return nil, err
+	}
+
+	if branch == "" {
+		return nil, errors.New("nothing to undo, repository has no commits")
+	}
+
+	head, err := b.Git.Repository.Head()
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving HEAD")
+	}
+
+	commits, err := b.Git.Repository.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading commit history")
+	}
+
+	commit, err := commits.Next()
+	commits.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading commit history")
+	}
+
+	if len(commit.ParentHashes) != 1 {
+		return nil, errors.Errorf("refusing to undo commit %v, it has %v parents", commit.Hash.String()[:7], len(commit.ParentHashes))
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading commit history")
+	}
+
+	u := &undo{Commit: commit, Parent: parent, Branch: branch}
+
+	u.Changes, err = commitChanges(parent, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := b.Git.releaseTags()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range tags[commit.Hash] {
+		if v := b.Git.parseTag(name); u.Version == nil || v.GreaterThan(u.Version) {
+			u.Tag = name
+			u.Version = v
+		}
+	}
+
+	var releaseAs string
+	for _, t := range trailerBlock(commit.Message) {
+		if key, value, _ := strings.Cut(t, ":"); key == TrailerReleaseAs {
+			releaseAs = strings.TrimSpace(value)
+		}
+	}
+
+	if releaseAs != "" {
+		v, err := semver.StrictNewVersion(releaseAs)
+		if err != nil {
+			return nil, errors.Errorf("HEAD is not a release commit of version-bump, %v trailer has an invalid version %v", TrailerReleaseAs, releaseAs)
+		}
+
+		if u.Version != nil && !u.Version.Equal(v) {
+			return nil, errors.Errorf("release commit of version %v is tagged as %v", v, u.Tag)
+		}
+
+		u.Version = v
+	} else {
+		matched := false
+		if u.Version != nil {
+			matched, err = b.matchesTemplate(u)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !matched {
+			return nil, errors.Errorf("HEAD is not a release commit of version-bump, it has neither a %v trailer nor a release tag and a message matching the commit message template", TrailerReleaseAs)
+		}
+	}
+
+	if b.Configuration.Git.ReleaseBranch {
+		name, err := b.releaseBranchName(u.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		ref, err := b.Git.References.Reference(plumbing.NewBranchReferenceName(name))
+		if err == nil && ref.Hash() == commit.Hash {
+			u.ReleaseBranch = name
+		} else if err != nil && err != plumbing.ErrReferenceNotFound {
+			return nil, errors.Wrapf(err, "error retrieving branch %v", name)
+		}
+	}
+
+	return u, nil
+}
+
+// matchesTemplate reports whether a commit message was rendered from the commit message template,
+// possibly followed by trailers or lines added by hooks.
+// The level of a release is unknown, thus the level derived from the previous version and 'set' are tried.
+func (b *Bump) matchesTemplate(u *undo) (bool, error) {
+	_, previous, err := b.Git.reachableTag(u.Parent.Hash)
+	if err != nil {
+		return false, err
+	}
+
+	data := TemplateData{
+		Version:   u.Version.String(),
+		Date:      u.Commit.Committer.When.Format("2006-01-02"),
+		Files:     changedPaths(u.Changes),
+		Component: b.Configuration.Git.Component,
+	}
+
+	if previous != nil {
+		data.PreviousVersion = previous.String()
+	}
+
+	message := strings.TrimRight(u.Commit.Message, "\n")
+	for _, level := range []string{levelName(releaseLevel(previous, u.Version)), levelName(0)} {
+		data.Level = level
+
+		rendered, err := render("commit_message", b.Configuration.Git.CommitMessage, DefaultCommitMessage, data)
+		if err != nil {
+			return false, err
+		}
+
+		rendered = strings.TrimRight(rendered, "\n")
+		if message == rendered || strings.HasPrefix(message, rendered+"\n") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// undoRemote reverts a pushed release on the remote: the release tag and the release branch are deleted,
+// and the branch is reset to the parent of the release commit when it points at the release commit.
+// Without remote, a pushed release is refused, and a remote that can not be reached is only required by a tagged release,
+// since tags have no remote-tracking references to tell whether they were pushed.
+func (b *Bump) undoRemote(u *undo, remote bool) error {
+	name := b.remote()
+
+	if _, err := b.Git.Repository.Remote(name); err == git.ErrRemoteNotFound && !remote {
+		return nil
+	}
+
+	r, auth, err := b.Git.connect(name)
+	var refs []*plumbing.Reference
+	if err == nil {
+		refs, err = b.Git.remoteRefs(r, name, auth)
+	}
+
+	if err != nil {
+		if remote {
+			return err
+		}
+
+		if u.Tag != "" {
+			return errors.Wrapf(err, "refusing to undo a release tagged %v, whether the tag was pushed to remote %v is unknown", u.Tag, name)
+		}
+
+		console.RemoteUnavailable(name, err)
+
+		refs, err = b.trackingRefs(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	pushed := make([]string, 0)
+	specs := make([]config.RefSpec, 0)
+	for _, ref := range refs {
+		switch {
+		case u.Tag != "" && ref.Name() == plumbing.NewTagReferenceName(u.Tag):
+			pushed = append(pushed, fmt.Sprintf("tag %v", u.Tag))
+			specs = append(specs, config.RefSpec(fmt.Sprintf(":%v", ref.Name())))
+		case ref.Name() == plumbing.NewBranchReferenceName(u.Branch) && ref.Hash() == u.Commit.Hash:
+			pushed = append(pushed, fmt.Sprintf("branch %v", u.Branch))
+			specs = append(specs, config.RefSpec(fmt.Sprintf("+%v:%v", u.Parent.Hash, ref.Name())))
+		case u.ReleaseBranch != "" && ref.Name() == plumbing.NewBranchReferenceName(u.ReleaseBranch) && ref.Hash() == u.Commit.Hash:
+			pushed = append(pushed, fmt.Sprintf("release branch %v", u.ReleaseBranch))
+			specs = append(specs, config.RefSpec(fmt.Sprintf(":%v", ref.Name())))
+		}
+	}
+
+	if len(pushed) == 0 {
+		return nil
+	}
+
+	sort.Strings(pushed)
+	if !remote {
+		return errors.Errorf("release was pushed to remote %v (%v), use --remote to undo it on the remote as well", name, strings.Join(pushed, ", "))
+	}
+
+	console.UndoingRemote(name)
+
+	for _, spec := range specs {
+		if err := b.Git.pushSpec(name, spec, auth); err != nil {
+			return errors.Wrapf(err, "error undoing release on remote %v", name)
+		}
+	}
+
+	return nil
+}
+
+// trackingRefs returns the branches of a remote as they were last fetched or pushed, named like on the remote.
+// NOTE: tags have no remote-tracking references, thus a pushed tag is not detected by them.
+func (b *Bump) trackingRefs(remote string) ([]*plumbing.Reference, error) {
+	iter, err := b.Git.References.IterReferences()
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing references")
+	}
+
+	prefix := fmt.Sprintf("refs/remotes/%v/", remote)
+	refs := make([]*plumbing.Reference, 0)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+			branch := plumbing.NewBranchReferenceName(strings.TrimPrefix(ref.Name().String(), prefix))
+			refs = append(refs, plumbing.NewHashReference(branch, ref.Hash()))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing references")
+	}
+
+	return refs, nil
+}
+
+// restore writes a file changed by a release commit as it was in the parent commit
+func (b *Bump) restore(c *object.Change, parent *object.Commit) error {
+	// NOTE: a file added by a release commit is removed
+	if c.From.Name == "" {
+		if err := b.FS.Remove(c.To.Name); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error removing a file %v", c.To.Name)
+		}
+
+		return nil
+	}
+
+	file, err := parent.File(c.From.Name)
+	if err != nil {
+		return errors.Wrapf(err, "error reading a file %v", c.From.Name)
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return errors.Wrapf(err, "error reading a file %v", c.From.Name)
+	}
+
+	// NOTE: permissions of a worktree file are preserved, a file that is gone gets the mode of the tree entry
+	mode := os.FileMode(0644)
+	if info, err := b.FS.Stat(c.From.Name); err == nil {
+		mode = info.Mode().Perm()
+	} else if file.Mode == filemode.Executable {
+		mode = 0755
+	}
+
+	console.RestoringFile(c.From.Name)
+
+	if err := writeFile(b.FS, c.From.Name, content, mode); err != nil {
+		return errors.Wrapf(err, "error restoring a file %v", c.From.Name)
+	}
+
+	return nil
+}
+
+// commitChanges returns the files changed by a commit
+func commitChanges(parent, commit *object.Commit) (object.Changes, error) {
+	from, err := parent.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading a commit tree")
+	}
+
+	to, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading a commit tree")
+	}
+
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "error comparing commit trees")
+	}
+
+	return changes, nil
+}
+
+// changedPaths returns the sorted paths of changed files
+func changedPaths(changes object.Changes) []string {
+	res := make([]string, 0, len(changes))
+	for _, c := range changes {
+		if c.From.Name != "" {
+			res = append(res, c.From.Name)
+		} else {
+			res = append(res, c.To.Name)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
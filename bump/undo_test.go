@@ -0,0 +1,277 @@
+package bump_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	a := assert.New(t)
+
+	type test struct {
+		Config string
+		Action int
+		// NOTE: HEAD is released before undoing it
+		Released bool
+		// NOTE: a bare repository is registered as an origin remote
+		Remote bool
+		// NOTE: undo on the remote as well
+		UndoRemote bool
+		// NOTE: the remote can not be reached after a release
+		Unreachable bool
+		// NOTE: the remote-tracking branch points at the release commit
+		Tracking bool
+		// NOTE: files written after a release, a file ending with '+' is staged
+		Files          map[string]string
+		ExpectedTag    string
+		ExpectedStatus string
+		ExpectedError  string
+	}
+
+	suite := map[string]test{
+		"Release": {
+			Action:      bump.Patch,
+			Released:    true,
+			ExpectedTag: "v1.2.4",
+		},
+		"Unrelated Changes": {
+			Action:   bump.Patch,
+			Released: true,
+			Files: map[string]string{
+				"README.md":   "# Changed\n",
+				"notes.txt+":  "notes\n",
+				"draft.txt":   "draft\n",
+				"version.txt": "",
+			},
+			ExpectedTag:    "v1.2.4",
+			ExpectedStatus: " M README.md\nA  notes.txt\n?? draft.txt\n?? version.txt\n",
+		},
+		"Commit Message Template": {
+			Config:      "commit_message = 'chore(release): {{.Version}} ({{.Level}} after {{.PreviousVersion}})'\n",
+			Action:      bump.Minor,
+			Released:    true,
+			ExpectedTag: "v1.3.0",
+		},
+		"Version Trailers Without Tag": {
+			Config:   "no_tag = true\nversion_trailers = true\ncommit_message = 'Release'\n",
+			Action:   bump.Patch,
+			Released: true,
+		},
+		"Release Branch": {
+			Config:      "release_branch = true\n",
+			Action:      bump.Minor,
+			Released:    true,
+			ExpectedTag: "v1.3.0",
+		},
+		"Git Backend": {
+			Config:      "backend = 'git'\n",
+			Action:      bump.Patch,
+			Released:    true,
+			ExpectedTag: "v1.2.4",
+		},
+		"Not a Release": {
+			ExpectedError: "HEAD is not a release commit of version-bump, it has neither a Release-As trailer nor a release tag and a message matching the commit message template",
+		},
+		"Modified Release File": {
+			Action:   bump.Patch,
+			Released: true,
+			Files: map[string]string{
+				"version.go": "package main\n\nconst Version string = \"1.2.5\"\n",
+			},
+			ExpectedError: "file version.go was modified after the release, commit or stash the changes before undoing it",
+		},
+		"Unpushed Release": {
+			Action:      bump.Patch,
+			Released:    true,
+			Remote:      true,
+			ExpectedTag: "v1.2.4",
+		},
+		"Pushed Release": {
+			Config:        "push = true\n",
+			Action:        bump.Patch,
+			Released:      true,
+			Remote:        true,
+			ExpectedError: "release was pushed to remote origin (branch master, tag v1.2.4), use --remote to undo it on the remote as well",
+		},
+		"Pushed Release - Remote": {
+			Config:      "push = true\n",
+			Action:      bump.Patch,
+			Released:    true,
+			Remote:      true,
+			UndoRemote:  true,
+			ExpectedTag: "v1.2.4",
+		},
+		"Unreachable Remote": {
+			Action:        bump.Patch,
+			Released:      true,
+			Remote:        true,
+			Unreachable:   true,
+			ExpectedError: "refusing to undo a release tagged v1.2.4, whether the tag was pushed to remote origin is unknown: error listing references of remote origin: repository not found",
+		},
+		"Unreachable Remote - Untagged Release": {
+			Config:      "no_tag = true\nversion_trailers = true\ncommit_message = 'Release'\n",
+			Action:      bump.Patch,
+			Released:    true,
+			Remote:      true,
+			Unreachable: true,
+		},
+		"Unreachable Remote - Pushed Branch": {
+			Config:        "no_tag = true\nversion_trailers = true\ncommit_message = 'Release'\n",
+			Action:        bump.Patch,
+			Released:      true,
+			Remote:        true,
+			Unreachable:   true,
+			Tracking:      true,
+			ExpectedError: "release was pushed to remote origin (branch master), use --remote to undo it on the remote as well",
+		},
+		"Unreachable Remote - Remote": {
+			Action:        bump.Patch,
+			Released:      true,
+			Remote:        true,
+			Unreachable:   true,
+			UndoRemote:    true,
+			ExpectedError: "error listing references of remote origin: repository not found",
+		},
+		"Pushed Release - Remote - Git Backend": {
+			Config:      "push = true\nbackend = 'git'\n",
+			Action:      bump.Patch,
+			Released:    true,
+			Remote:      true,
+			UndoRemote:  true,
+			ExpectedTag: "v1.2.4",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, repo := project(t, test.Config)
+
+		var bare *git.Repository
+		if test.Remote {
+			bare = remote(t, repo)
+		}
+
+		before, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+
+		if test.Released {
+			b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+			if err != nil {
+				t.Fatalf("error preparing test case: %v", err)
+			}
+
+			if err := b.Bump(test.Action); err != nil {
+				t.Fatalf("error preparing test case: error releasing: %v", err)
+			}
+		}
+
+		// NOTE: permissions that git does not track are preserved
+		if err := os.Chmod(filepath.Join(dir, "version.go"), 0640); err != nil {
+			t.Fatalf("error preparing test case: error changing permissions: %v", err)
+		}
+
+		released, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+
+		if test.Tracking {
+			if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewRemoteReferenceName("origin", "master"), released.Hash())); err != nil {
+				t.Fatalf("error preparing test case: error updating remote-tracking branch: %v", err)
+			}
+		}
+
+		if test.Unreachable {
+			if err := repo.DeleteRemote("origin"); err != nil {
+				t.Fatalf("error preparing test case: error deleting remote: %v", err)
+			}
+
+			if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{filepath.Join(dir, "missing")}}); err != nil {
+				t.Fatalf("error preparing test case: error creating remote: %v", err)
+			}
+		}
+
+		for path, content := range test.Files {
+			staged := path[len(path)-1] == '+'
+			if staged {
+				path = path[:len(path)-1]
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+				t.Fatalf("error preparing test case: error writing %v: %v", path, err)
+			}
+
+			if staged {
+				if out, err := exec.Command("git", "-C", dir, "add", path).CombinedOutput(); err != nil {
+					t.Fatalf("error preparing test case: error staging %v: %v: %s", path, err, out)
+				}
+			}
+		}
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+
+		err = b.Undo(test.UndoRemote)
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+
+			head, err := repo.Head()
+			a.Equal(nil, err)
+			a.Equal(released.Hash(), head.Hash())
+			continue
+		}
+
+		head, err := repo.Head()
+		a.Equal(nil, err)
+		a.Equal(before.Hash(), head.Hash())
+
+		content, _ := os.ReadFile(filepath.Join(dir, "version.go"))
+		a.Contains(string(content), "\"1.2.3\"")
+
+		if info, err := os.Stat(filepath.Join(dir, "version.go")); a.Equal(nil, err) {
+			a.Equal(os.FileMode(0640), info.Mode().Perm())
+		}
+
+		out, err := exec.Command("git", "-C", dir, "status", "--porcelain").CombinedOutput()
+		a.Equal(nil, err)
+		a.Equal(test.ExpectedStatus, string(out))
+
+		if test.ExpectedTag != "" {
+			_, err = repo.Tag(test.ExpectedTag)
+			a.Equal(git.ErrTagNotFound, err)
+		}
+
+		_, err = repo.Reference(plumbing.NewBranchReferenceName("release/1.3"), false)
+		a.Equal(plumbing.ErrReferenceNotFound, err)
+
+		if test.UndoRemote {
+			branch, err := bare.Reference(plumbing.NewBranchReferenceName("master"), false)
+			a.Equal(nil, err)
+			a.Equal(before.Hash(), branch.Hash())
+
+			_, err = bare.Reference(plumbing.NewTagReferenceName(test.ExpectedTag), false)
+			a.Equal(plumbing.ErrReferenceNotFound, err)
+		}
+	}
+}
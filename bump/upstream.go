@@ -0,0 +1,165 @@
+package bump
+
+import (
+	"fmt"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+)
+
+// remoteLister lists references of a remote by itself, as the git backend does with 'git ls-remote'
+type remoteLister interface {
+	listRemote(string) ([]*plumbing.Reference, error)
+}
+
+// VerifyUpstream fetches a remote and ensures that the current branch tracks a branch of the remote
+// and is not behind it, and that a release tag, unless empty, does not exist on the remote yet.
+// Without fetch, references of the remote are only listed, leaving remote-tracking branches untouched.
+func (g *GitConfig) VerifyUpstream(remote, tag string, fetch bool) error {
+	name, err := g.Branch()
+	if err != nil {
+		return err
+	}
+
+	branch, err := g.Repository.Branch(name)
+	if err == git.ErrBranchNotFound || (err == nil && (branch.Remote == "" || branch.Merge == "")) {
+		return errors.Errorf("branch %v has no upstream, set it with 'git push --set-upstream %v %v'", name, remote, name)
+	} else if err != nil {
+		return errors.Wrapf(err, "error retrieving upstream of branch %v", name)
+	}
+
+	if branch.Remote != remote {
+		return errors.Errorf("upstream of branch %v is on remote %v, but releases are pushed to remote %v", name, branch.Remote, remote)
+	}
+
+	r, auth, err := g.connect(remote)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: an upstream branch is mapped to a remote-tracking branch by fetch refspecs of the remote
+	var tracking plumbing.ReferenceName
+	for _, spec := range r.Config().Fetch {
+		if spec.Match(branch.Merge) {
+			tracking = spec.Dst(branch.Merge)
+		}
+	}
+
+	var refs []*plumbing.Reference
+	var upstream *plumbing.Reference
+	if fetch {
+		err = g.Repository.Fetch(&git.FetchOptions{RemoteName: remote, Tags: git.NoTags, Auth: auth})
+		// NOTE: nothing is fetched from an empty remote, thus the upstream branch is found missing below
+		if err != nil && err != git.NoErrAlreadyUpToDate && err != transport.ErrEmptyRemoteRepository {
+			return errors.Wrapf(err, "error fetching from remote %v", remote)
+		}
+
+		if tracking != "" {
+			upstream, err = g.References.Reference(tracking)
+			if err != nil && err != plumbing.ErrReferenceNotFound {
+				return errors.Wrapf(err, "error retrieving upstream branch %v", tracking.Short())
+			}
+		}
+	} else {
+		refs, err = g.remoteRefs(r, remote, auth)
+		if err != nil {
+			return err
+		}
+
+		for _, ref := range refs {
+			if ref.Name() == branch.Merge {
+				upstream = ref
+			}
+		}
+	}
+
+	if upstream == nil {
+		return errors.Errorf("upstream branch %v of branch %v does not exist on remote %v", branch.Merge.Short(), name, remote)
+	}
+
+	// NOTE: an upstream commit that was not fetched is missing from the history of HEAD as well
+	behind, err := g.behind(upstream.Hash())
+	if err != nil {
+		return err
+	}
+
+	if tracking == "" {
+		tracking = plumbing.NewRemoteReferenceName(remote, branch.Merge.Short())
+	}
+
+	if behind {
+		return errors.Errorf("branch %v is behind %v, pull the remote changes before releasing", name, tracking.Short())
+	}
+
+	if tag == "" {
+		return nil
+	}
+
+	if fetch {
+		refs, err = g.remoteRefs(r, remote, auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == plumbing.NewTagReferenceName(tag) {
+			return &TagError{Tag: tag, Reason: fmt.Sprintf("tag already exists on remote %v", remote)}
+		}
+	}
+
+	return nil
+}
+
+// behind reports whether a commit is missing from the history of HEAD
+func (g *GitConfig) behind(hash plumbing.Hash) (bool, error) {
+	head, err := g.Repository.Head()
+	if err != nil {
+		return false, errors.Wrap(err, "error resolving HEAD")
+	}
+
+	commits, err := g.Repository.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return false, errors.Wrap(err, "error reading commit history")
+	}
+
+	found := false
+	err = commits.ForEach(func(c *object.Commit) error {
+		if c.Hash == hash {
+			found = true
+			return storer.ErrStop
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "error reading commit history")
+	}
+
+	return !found, nil
+}
+
+// remoteRefs lists references of a remote
+func (g *GitConfig) remoteRefs(r *git.Remote, remote string, auth transport.AuthMethod) ([]*plumbing.Reference, error) {
+	var refs []*plumbing.Reference
+	var err error
+	if lister, ok := g.Repository.(remoteLister); ok {
+		refs, err = lister.listRemote(remote)
+	} else {
+		refs, err = r.List(&git.ListOptions{Auth: auth})
+	}
+
+	if err == transport.ErrEmptyRemoteRepository {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listing references of remote %v", remote)
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,220 @@
+package bump_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+	"version-bump/bump"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckUpstream(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	a := assert.New(t)
+
+	type test struct {
+		Config string
+		// NOTE: remote of the upstream of the current branch, no upstream when empty
+		Upstream string
+		// NOTE: the current branch is pushed to the remote
+		Published bool
+		// NOTE: the remote has a commit that the current branch has not
+		Behind bool
+		// NOTE: the remote has the release tag
+		RemoteTag     bool
+		DryRun        bool
+		ExpectedTag   bool
+		ExpectedError string
+	}
+
+	suite := map[string]test{
+		"Up To Date": {
+			Upstream:    "origin",
+			Published:   true,
+			ExpectedTag: true,
+		},
+		"No Upstream": {
+			Published:     true,
+			ExpectedError: "branch master has no upstream, set it with 'git push --set-upstream origin master'",
+		},
+		"Upstream on Another Remote": {
+			Upstream:      "fork",
+			Published:     true,
+			ExpectedError: "upstream of branch master is on remote fork, but releases are pushed to remote origin",
+		},
+		"Unpublished Branch": {
+			Upstream:      "origin",
+			ExpectedError: "upstream branch master of branch master does not exist on remote origin",
+		},
+		"Behind": {
+			Upstream:      "origin",
+			Published:     true,
+			Behind:        true,
+			ExpectedError: "branch master is behind origin/master, pull the remote changes before releasing",
+		},
+		"Tag on Remote": {
+			Upstream:      "origin",
+			Published:     true,
+			RemoteTag:     true,
+			ExpectedError: "refusing to create tag v1.2.4: tag already exists on remote origin",
+		},
+		"Tag on Remote - No Tag": {
+			Config:    "no_tag = true\n",
+			Upstream:  "origin",
+			Published: true,
+			RemoteTag: true,
+		},
+		"Dry Run - Up To Date": {
+			Upstream:  "origin",
+			Published: true,
+			DryRun:    true,
+		},
+		"Dry Run - Unpublished Branch": {
+			Upstream:      "origin",
+			DryRun:        true,
+			ExpectedError: "upstream branch master of branch master does not exist on remote origin",
+		},
+		"Dry Run - Behind": {
+			Upstream:      "origin",
+			Published:     true,
+			Behind:        true,
+			DryRun:        true,
+			ExpectedError: "branch master is behind origin/master, pull the remote changes before releasing",
+		},
+		"Dry Run - Tag on Remote": {
+			Upstream:      "origin",
+			Published:     true,
+			RemoteTag:     true,
+			DryRun:        true,
+			ExpectedError: "refusing to create tag v1.2.4: tag already exists on remote origin",
+		},
+		"Git Backend - Up To Date": {
+			Config:      "backend = 'git'\n",
+			Upstream:    "origin",
+			Published:   true,
+			ExpectedTag: true,
+		},
+		"Git Backend - Behind": {
+			Config:        "backend = 'git'\n",
+			Upstream:      "origin",
+			Published:     true,
+			Behind:        true,
+			ExpectedError: "branch master is behind origin/master, pull the remote changes before releasing",
+		},
+		"Git Backend - Tag on Remote": {
+			Config:        "backend = 'git'\n",
+			Upstream:      "origin",
+			Published:     true,
+			RemoteTag:     true,
+			ExpectedError: "refusing to create tag v1.2.4: tag already exists on remote origin",
+		},
+		"Git Backend - Dry Run - Behind": {
+			Config:        "backend = 'git'\n",
+			Upstream:      "origin",
+			Published:     true,
+			Behind:        true,
+			DryRun:        true,
+			ExpectedError: "branch master is behind origin/master, pull the remote changes before releasing",
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		dir, repo := project(t, "check_upstream = true\n"+test.Config)
+		bare := remote(t, repo)
+
+		head, err := repo.Head()
+		if err != nil {
+			t.Fatalf("error preparing test case: error retrieving HEAD: %v", err)
+		}
+
+		if test.Published {
+			if err := repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/master:refs/heads/master"}}); err != nil {
+				t.Fatalf("error preparing test case: error pushing branch: %v", err)
+			}
+		}
+
+		if test.Upstream != "" {
+			if err := repo.CreateBranch(&config.Branch{Name: "master", Remote: test.Upstream, Merge: plumbing.NewBranchReferenceName("master")}); err != nil {
+				t.Fatalf("error preparing test case: error setting upstream: %v", err)
+			}
+		}
+
+		if test.Behind {
+			parent, err := repo.CommitObject(head.Hash())
+			if err != nil {
+				t.Fatalf("error preparing test case: error retrieving commit: %v", err)
+			}
+
+			// NOTE: a commit pushed by someone else
+			commit := &object.Commit{
+				Author:       object.Signature{Name: username, Email: email, When: time.Now()},
+				Committer:    object.Signature{Name: username, Email: email, When: time.Now()},
+				Message:      "other changes",
+				TreeHash:     parent.TreeHash,
+				ParentHashes: []plumbing.Hash{head.Hash()},
+			}
+
+			obj := bare.Storer.NewEncodedObject()
+			if err := commit.Encode(obj); err != nil {
+				t.Fatalf("error preparing test case: error encoding commit: %v", err)
+			}
+
+			hash, err := bare.Storer.SetEncodedObject(obj)
+			if err != nil {
+				t.Fatalf("error preparing test case: error writing commit: %v", err)
+			}
+
+			if err := bare.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), hash)); err != nil {
+				t.Fatalf("error preparing test case: error updating remote branch: %v", err)
+			}
+		}
+
+		if test.RemoteTag {
+			if err := bare.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.2.4"), head.Hash())); err != nil {
+				t.Fatalf("error preparing test case: error creating remote tag: %v", err)
+			}
+		}
+
+		tracking, _ := repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), false)
+
+		b, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), dir), osfs.New(filepath.Join(dir, ".git")), osfs.New(dir), ".")
+		if err != nil {
+			t.Fatalf("error preparing test case: %v", err)
+		}
+		b.DryRun = test.DryRun
+
+		err = b.Bump(bump.Patch)
+
+		// NOTE: a dry-run does not fetch, thus remote-tracking branches are untouched
+		if test.DryRun {
+			current, _ := repo.Reference(plumbing.NewRemoteReferenceName("origin", "master"), false)
+			a.Equal(tracking, current)
+		}
+
+		if test.ExpectedError != "" || err != nil {
+			a.EqualError(err, test.ExpectedError)
+
+			current, err := repo.Head()
+			a.Equal(nil, err)
+			a.Equal(head.Hash(), current.Hash())
+		}
+
+		_, err = repo.Tag("v1.2.4")
+		a.Equal(test.ExpectedTag, err == nil)
+	}
+}
@@ -2,6 +2,8 @@ package bump
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"os"
 	"path"
 	"strings"
@@ -52,32 +54,122 @@ func filterFiles(configNames []string, files []string) []string {
 	return res
 }
 
-func readFile(fs afero.Fs, filepath string) ([]string, error) {
-	lines := make([]string, 0)
+// document is a text file split into lines, which keeps every detail required to write it back byte for byte
+type document struct {
+	BOM   bool
+	Lines []string
+	EOLs  []string
+	Mode  os.FileMode
+}
+
+const bom string = "\xEF\xBB\xBF"
+
+func readFile(fs afero.Fs, filepath string) (*document, error) {
+	doc := &document{
+		Lines: make([]string, 0),
+		EOLs:  make([]string, 0),
+	}
 
 	file, err := fs.Open(filepath)
 	if err != nil {
-		return lines, err
+		return doc, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	info, err := file.Stat()
+	if err != nil {
+		return doc, err
+	}
+	doc.Mode = info.Mode().Perm()
+
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		size += int64(len(line))
+
+		if line != "" {
+			if len(doc.Lines) == 0 && strings.HasPrefix(line, bom) {
+				doc.BOM = true
+				line = strings.TrimPrefix(line, bom)
+			}
+
+			var eol string
+			switch {
+			case strings.HasSuffix(line, "\r\n"):
+				eol = "\r\n"
+			case strings.HasSuffix(line, "\n"):
+				eol = "\n"
+			}
+
+			doc.Lines = append(doc.Lines, strings.TrimSuffix(line, eol))
+			doc.EOLs = append(doc.EOLs, eol)
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return doc, errors.Wrap(err, "error reading a file")
+		}
+	}
+
+	if info.Mode().IsRegular() && size != info.Size() {
+		return doc, errors.Errorf("file was not fully read: %v out of %v bytes", size, info.Size())
+	}
+
+	return doc, nil
+}
+
+// Text returns the content of a document without a byte order mark
+func (d *document) Text() string {
+	var sb strings.Builder
+
+	for i, l := range d.Lines {
+		sb.WriteString(l)
+		sb.WriteString(d.EOLs[i])
+	}
+
+	return sb.String()
+}
+
+// String returns the exact content of a document
+func (d *document) String() string {
+	if d.BOM {
+		return bom + d.Text()
 	}
 
-	return lines, nil
+	return d.Text()
 }
 
-func writeFile(fs afero.Fs, filepath string, content string) error {
-	file, err := fs.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+// writeFile atomically replaces the content of a file, by writing to a temporary file that is renamed afterwards
+func writeFile(fs afero.Fs, filepath string, content string, mode os.FileMode) error {
+	file, err := afero.TempFile(fs, path.Dir(filepath), fmt.Sprintf(".%v.*", path.Base(filepath)))
 	if err != nil {
-		return errors.Wrap(err, "error opening a file")
+		return errors.Wrap(err, "error creating a temporary file")
 	}
+
+	if err := write(file, content); err != nil {
+		_ = fs.Remove(file.Name())
+		return err
+	}
+
+	if err := fs.Chmod(file.Name(), mode); err != nil {
+		_ = fs.Remove(file.Name())
+		return errors.Wrap(err, "error setting file permissions")
+	}
+
+	if err := fs.Rename(file.Name(), filepath); err != nil {
+		_ = fs.Remove(file.Name())
+		return errors.Wrap(err, "error replacing a file")
+	}
+
+	return nil
+}
+
+func write(file afero.File, content string) error {
 	defer file.Close()
 
-	_, err = file.WriteString(content)
+	_, err := file.WriteString(content)
 	if err != nil {
 		return errors.Wrap(err, "error writing to file")
 	}
@@ -88,3 +180,38 @@ func writeFile(fs afero.Fs, filepath string, content string) error {
 
 	return nil
 }
+
+// substituted reports whether the only difference between two contents is
+// the replacement of some occurrences of an old version with a new version
+func substituted(before, after, oldVersion, newVersion string) bool {
+	if oldVersion == "" || oldVersion == newVersion {
+		return before == after
+	}
+
+	var i, j int
+	for {
+		var k int
+		for i+k < len(before) && j+k < len(after) && before[i+k] == after[j+k] {
+			k++
+		}
+
+		if i+k == len(before) && j+k == len(after) {
+			return true
+		}
+
+		// NOTE: find the closest substitution that covers the difference
+		s := k
+		for ; s >= 0; s-- {
+			if strings.HasPrefix(before[i+s:], oldVersion) && strings.HasPrefix(after[j+s:], newVersion) {
+				break
+			}
+		}
+
+		if s < 0 {
+			return false
+		}
+
+		i += s + len(oldVersion)
+		j += s + len(newVersion)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"path"
 	"sync"
 	"time"
 	"version-bump/bump"
@@ -14,10 +13,62 @@ import (
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 )
 
-func run(action int) {
+// exit code of a release tag conflict
+const exitTagConflict int = 3
+
+var (
+	dryRun        bool
+	emptyCommit   bool
+	versionSource string
+	allowDirty    bool
+	push          bool
+	sign          bool
+	noSign        bool
+	signingKey    string
+	signingFormat string
+	noVerify      bool
+	noCommit      bool
+	noTag         bool
+	signOff       bool
+	trailers      []string
+	checkUpstream bool
+)
+
+// dryRunFlag registers the flag of commands that print their changes instead of applying them
+func dryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes without modifying the project")
+}
+
+// releaseFlags registers the flags of commands that release a new version
+func releaseFlags(cmd *cobra.Command) {
+	dryRunFlag(cmd)
+	cmd.Flags().BoolVar(&emptyCommit, "empty-commit", false, "create an empty commit when a project without version files is tagged")
+	gitFlags(cmd)
+	cmd.Flags().StringVar(&versionSource, "version-source", "", "source of the current version: files or tags")
+}
+
+// gitFlags registers the flags of commands that commit and tag a release
+func gitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "release a worktree with uncommitted changes, which are left out of the release commit")
+	cmd.Flags().BoolVar(&push, "push", false, "push the current branch and the release tag to a remote")
+	cmd.Flags().BoolVarP(&sign, "sign", "s", false, "sign the release commit and tag, regardless of git configuration")
+	cmd.Flags().BoolVar(&noSign, "no-sign", false, "do not sign the release commit and tag, regardless of git configuration")
+	cmd.Flags().StringVar(&signingKey, "signing-key", "", "key to sign with, overrides user.signingkey")
+	cmd.Flags().StringVar(&signingFormat, "signing-format", "", "signature format: openpgp or ssh, overrides gpg.format")
+	cmd.Flags().BoolVarP(&noVerify, "no-verify", "n", false, "bypass pre-commit and commit-msg hooks of the release commit")
+	cmd.Flags().BoolVar(&noCommit, "no-commit", false, "update version files without committing, tagging or pushing them")
+	cmd.Flags().BoolVar(&noTag, "no-tag", false, "commit the release without tagging it")
+	cmd.Flags().BoolVar(&signOff, "signoff", false, "add a Signed-off-by trailer of the committer to the release commit")
+	cmd.Flags().StringArrayVar(&trailers, "trailer", nil, "add a 'key: value' or 'key=value' trailer to the release commit, may be repeated")
+	cmd.Flags().BoolVar(&checkUpstream, "check-upstream", false, "fetch the remote and verify that the current branch is not behind its upstream and the release tag is not on the remote")
+	cmd.MarkFlagsMutuallyExclusive("sign", "no-sign")
+}
+
+func run(action func(*bump.Bump) error) {
 	// check for an update in parallel
 	updateVersion := make(chan string, 1)
 	updateVersionError := make(chan error, 1)
@@ -25,19 +76,57 @@ func run(action int) {
 	wg.Add(1)
 	go getLatestVersion(&wg, updateVersion, updateVersionError)
 
-	dir := "."
-	p, err := bump.New(afero.NewOsFs(), osfs.New(path.Join(dir, ".git")), osfs.New(dir), dir)
-	if err != nil {
-		console.Fatal(errors.Wrap(err, "error preparing project configuration"))
+	p := project()
+	p.DryRun = dryRun
+	p.AllowDirty = allowDirty
+	if emptyCommit {
+		p.Configuration.Git.EmptyCommit = true
+	}
+	if push {
+		p.Configuration.Git.Push = true
+	}
+	if sign || noSign {
+		p.Git.Signing.Commit = sign
+		p.Git.Signing.Tag = sign
+	}
+	if signingKey != "" {
+		p.Git.Signing.Key = signingKey
+	}
+	if signingFormat != "" {
+		p.Git.Signing.Format = signingFormat
+	}
+	if noVerify {
+		p.Git.Hooks.NoVerify = true
+	}
+	if noCommit {
+		p.Configuration.Git.NoCommit = true
+	}
+	if noTag {
+		p.Configuration.Git.NoTag = true
+	}
+	if signOff {
+		p.Configuration.Git.SignOff = true
+	}
+	p.Configuration.Git.Trailers = append(p.Configuration.Git.Trailers, trailers...)
+	if checkUpstream {
+		p.Configuration.Git.CheckUpstream = true
+	}
+	if versionSource != "" {
+		p.Configuration.Git.Source = versionSource
 	}
 
-	if err := p.Bump(action); err != nil {
-		console.Fatal(errors.Wrap(err, "error bumping a version"))
+	if err := action(p); err != nil {
+		var tagErr *bump.TagError
+		if errors.As(err, &tagErr) {
+			console.FatalWithCode(err, exitTagConflict)
+		}
+
+		console.Fatal(err)
 	}
 
 	// notify user about an update
 	wg.Wait()
-	err = <-updateVersionError
+	err := <-updateVersionError
 	v := <-updateVersion
 	if err != nil {
 		console.ErrorCheckingForUpdate(err)
@@ -46,6 +135,38 @@ func run(action int) {
 	}
 }
 
+func project() *bump.Bump {
+	repo, err := bump.Discover(".")
+	if err != nil {
+		console.Fatal(errors.Wrap(err, "error preparing project configuration"))
+	}
+
+	// NOTE: project files are relative to the root of the worktree
+	p, err := bump.New(afero.NewBasePathFs(afero.NewOsFs(), repo.Root), repo.Metadata(), osfs.New(repo.Root), ".")
+	if err != nil {
+		console.Fatal(errors.Wrap(err, "error preparing project configuration"))
+	}
+
+	return p
+}
+
+// output writes a result to stdout in a requested format
+func output(format string, v interface{}, text func()) {
+	switch format {
+	case "json":
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			console.Fatal(errors.Wrap(err, "error encoding output"))
+		}
+
+		fmt.Println(string(out))
+	case "text":
+		text()
+	default:
+		console.Fatal(fmt.Sprintf("unsupported output format: %v", format))
+	}
+}
+
 func getLatestVersion(wg *sync.WaitGroup, version chan string, resultErr chan error) {
 	defer wg.Done()
 
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"version-bump/console"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify that the version is consistent across all files and the latest tag",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		console.CheckingProjectVersion()
+
+		res, err := project().Check()
+		if err != nil {
+			console.Fatal(errors.Wrap(err, "error checking a version"))
+		}
+
+		for _, f := range res.Files {
+			console.FileVersion(f.Language, f.File, f.Version.String())
+		}
+		console.LatestTag(res.Tag)
+
+		if len(res.Problems) > 0 {
+			for _, p := range res.Problems {
+				console.Problem(p)
+			}
+
+			console.Error(fmt.Sprintf("%v problem(s) found", len(res.Problems)))
+			os.Exit(1)
+		}
+
+		console.Success("Version is consistent")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
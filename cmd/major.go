@@ -3,6 +3,7 @@ package cmd
 import (
 	"version-bump/bump"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -10,10 +11,13 @@ var majorCmd = &cobra.Command{
 	Use:   "major",
 	Short: "Increment a major version",
 	Run: func(cmd *cobra.Command, args []string) {
-		run(bump.Major)
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Bump(bump.Major), "error bumping a version")
+		})
 	},
 }
 
 func init() {
+	releaseFlags(majorCmd)
 	rootCmd.AddCommand(majorCmd)
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"version-bump/bump"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -10,10 +11,13 @@ var minorCmd = &cobra.Command{
 	Use:   "minor",
 	Short: "Increment a minor version",
 	Run: func(cmd *cobra.Command, args []string) {
-		run(bump.Minor)
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Bump(bump.Minor), "error bumping a version")
+		})
 	},
 }
 
 func init() {
+	releaseFlags(minorCmd)
 	rootCmd.AddCommand(minorCmd)
 }
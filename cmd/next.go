@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"version-bump/bump"
+	"version-bump/console"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var nextFormat string
+
+var actions = map[string]int{
+	"major": bump.Major,
+	"minor": bump.Minor,
+	"patch": bump.Patch,
+}
+
+var nextCmd = &cobra.Command{
+	Use:       "next <major|minor|patch>",
+	Short:     "Print the next version without modifying the project",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"major", "minor", "patch"},
+	Run: func(cmd *cobra.Command, args []string) {
+		current, next, err := project().Next(actions[args[0]])
+		if err != nil {
+			console.Fatal(errors.Wrap(err, "error identifying a version"))
+		}
+
+		output(nextFormat, struct {
+			Current *semver.Version `json:"current"`
+			Next    *semver.Version `json:"next"`
+		}{current, next}, func() {
+			fmt.Println(next)
+		})
+	},
+}
+
+func init() {
+	nextCmd.Flags().StringVar(&nextFormat, "format", "text", "output format: text/json")
+	rootCmd.AddCommand(nextCmd)
+}
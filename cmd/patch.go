@@ -3,6 +3,7 @@ package cmd
 import (
 	"version-bump/bump"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -10,10 +11,13 @@ var patchCmd = &cobra.Command{
 	Use:   "patch",
 	Short: "Increment a patch version",
 	Run: func(cmd *cobra.Command, args []string) {
-		run(bump.Patch)
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Bump(bump.Patch), "error bumping a version")
+		})
 	},
 }
 
 func init() {
+	releaseFlags(patchCmd)
 	rootCmd.AddCommand(patchCmd)
 }
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"version-bump/bump"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var setForce bool
+
+var setCmd = &cobra.Command{
+	Use:   "set <version>",
+	Short: "Set an explicit version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Set(args[0], setForce), "error setting a version")
+		})
+	},
+}
+
+func init() {
+	setCmd.Flags().BoolVarP(&setForce, "force", "f", false, "allow a version that is not greater than the current one")
+	dryRunFlag(setCmd)
+	gitFlags(setCmd)
+	rootCmd.AddCommand(setCmd)
+}
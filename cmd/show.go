@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"version-bump/bump"
+	"version-bump/console"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var showFormat string
+
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the current version of the project",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		version, files, err := project().Current()
+		if err != nil {
+			console.Fatal(errors.Wrap(err, "error identifying a version"))
+		}
+
+		output(showFormat, struct {
+			Version *semver.Version    `json:"version"`
+			Files   []bump.FileVersion `json:"files"`
+		}{version, files}, func() {
+			fmt.Println(version)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, f := range files {
+				fmt.Fprintf(w, "  %v\t%v\t%v\n", f.Language, f.File, f.Version)
+			}
+			w.Flush()
+		})
+	},
+}
+
+func init() {
+	showCmd.Flags().StringVar(&showFormat, "format", "text", "output format: text/json")
+	rootCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"version-bump/bump"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [version]",
+	Short: "Align all files to a single version (highest found by default)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var version string
+		if len(args) == 1 {
+			version = args[0]
+		}
+
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Sync(version), "error synchronizing versions")
+		})
+	},
+}
+
+func init() {
+	dryRunFlag(syncCmd)
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"version-bump/bump"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var undoRemote bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last release: its commit, tag and file changes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		run(func(p *bump.Bump) error {
+			return errors.Wrap(p.Undo(undoRemote), "error undoing a release")
+		})
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoRemote, "remote", false, "undo a pushed release on the remote as well")
+	rootCmd.AddCommand(undoCmd)
+}
@@ -3,6 +3,7 @@ package console
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
 const (
@@ -17,10 +18,78 @@ func IncrementProjectVersion() {
 	fmt.Println("Incrementing project version...")
 }
 
+func SetProjectVersion() {
+	fmt.Println("Setting project version...")
+}
+
+func SyncProjectVersion(version string) {
+	fmt.Printf("Synchronizing project version to %v%v%v...\n",
+		string(colorGreen), version, string(colorReset),
+	)
+}
+
+func VersionsInSync() {
+	fmt.Println("  All files are already in sync")
+}
+
 func CommittingChanges() {
 	fmt.Println("Committing changes...")
 }
 
+func PushingChanges(remote string) {
+	fmt.Printf("Pushing changes to %v%v%v...\n",
+		string(colorCyan), remote, string(colorReset),
+	)
+}
+
+func CheckingUpstream(remote string) {
+	fmt.Printf("Checking upstream on remote %v%v%v...\n",
+		string(colorCyan), remote, string(colorReset),
+	)
+}
+
+func RollingBack() {
+	fmt.Printf("%vRestoring original files...%v\n",
+		string(colorYellow), string(colorReset),
+	)
+}
+
+func TaggingChanges() {
+	fmt.Println("Tagging changes...")
+}
+
+func TagUpdate(oldTag, newTag string) {
+	fmt.Printf("  Release tag:\n    %v%v%v -> %v%v%v\n",
+		string(colorYellow), oldTag, string(colorReset),
+		string(colorGreen), newTag, string(colorReset),
+	)
+}
+
+func TagSource(tag string) {
+	fmt.Printf("  Using latest release tag %v%v%v as the current version\n",
+		string(colorGreen), tag, string(colorReset),
+	)
+}
+
+func Drift(filepath, version, tag string) {
+	fmt.Printf("  %vDrift: %v has version %v, latest release tag is %v%v\n",
+		string(colorYellow), filepath, version, tag, string(colorReset),
+	)
+}
+
+func WouldTag(tag string, emptyCommit bool) {
+	if emptyCommit {
+		fmt.Printf("Would create an empty commit and tag %v%v%v\n",
+			string(colorCyan), tag, string(colorReset),
+		)
+		return
+	}
+
+	fmt.Printf("Would tag HEAD with %v%v%v\n",
+		string(colorCyan), tag, string(colorReset),
+	)
+}
+
 func Language(name string) {
 	fmt.Printf("  Updating %v%v%v files:\n",
 		string(colorCyan),
@@ -29,11 +98,156 @@ func Language(name string) {
 	)
 }
 
-func VersionUpdate(oldVersion, newVersion, filepath string) {
-	fmt.Printf("    %v%v%v -> %v%v%v %v\n",
+func VersionUpdate(oldVersion, newVersion, filepath string, occurrences int) {
+	suffix := "occurrences"
+	if occurrences == 1 {
+		suffix = "occurrence"
+	}
+
+	fmt.Printf("    %v%v%v -> %v%v%v %v (%v %v)\n",
 		string(colorYellow), oldVersion, string(colorReset),
 		string(colorGreen), newVersion, string(colorReset),
-		filepath,
+		filepath, occurrences, suffix,
+	)
+}
+
+func CheckingProjectVersion() {
+	fmt.Println("Checking project version...")
+}
+
+func FileVersion(language, filepath, version string) {
+	fmt.Printf("  %v%v%v %v %v\n",
+		string(colorCyan), language, string(colorReset),
+		filepath, version,
+	)
+}
+
+func LatestTag(tag string) {
+	if tag == "" {
+		tag = "none"
+	}
+
+	fmt.Printf("  Latest tag: %v\n", tag)
+}
+
+func Problem(msg interface{}) {
+	fmt.Printf("  %v✗ %v%v\n",
+		string(colorRed), msg, string(colorReset),
+	)
+}
+
+func Success(msg interface{}) {
+	fmt.Printf("%v%v%v\n",
+		string(colorGreen), msg, string(colorReset),
+	)
+}
+
+func DryRun() {
+	fmt.Printf("%vDry run, no changes were made%v\n",
+		string(colorYellow), string(colorReset),
+	)
+}
+
+func Diff(text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Println(line)
+		case strings.HasPrefix(line, "+"):
+			fmt.Printf("%v%v%v\n", string(colorGreen), line, string(colorReset))
+		case strings.HasPrefix(line, "-"):
+			fmt.Printf("%v%v%v\n", string(colorRed), line, string(colorReset))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Printf("%v%v%v\n", string(colorCyan), line, string(colorReset))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func WouldCommit(message, tag string) {
+	if tag == "" {
+		fmt.Printf("Would commit with message %v%q%v\n",
+			string(colorCyan), message, string(colorReset),
+		)
+		return
+	}
+
+	fmt.Printf("Would commit with message %v%q%v and create tag %v%v%v\n",
+		string(colorCyan), message, string(colorReset),
+		string(colorCyan), tag, string(colorReset),
+	)
+}
+
+func CreatingBranch(name string) {
+	fmt.Printf("Creating release branch %v%v%v...\n",
+		string(colorCyan), name, string(colorReset),
+	)
+}
+
+func WouldBranch(name string) {
+	fmt.Printf("Would create release branch %v%v%v\n",
+		string(colorCyan), name, string(colorReset),
+	)
+}
+
+func WouldPush(remote string, branch, tag bool) {
+	if branch && !tag {
+		fmt.Printf("Would push the current branch to %v%v%v\n",
+			string(colorCyan), remote, string(colorReset),
+		)
+		return
+	}
+
+	if branch {
+		fmt.Printf("Would push the current branch and the tag to %v%v%v\n",
+			string(colorCyan), remote, string(colorReset),
+		)
+		return
+	}
+
+	fmt.Printf("Would push the tag to %v%v%v\n",
+		string(colorCyan), remote, string(colorReset),
+	)
+}
+
+func UndoingRelease(version, commit string) {
+	fmt.Printf("Undoing release %v%v%v (commit %v)...\n",
+		string(colorGreen), version, string(colorReset), commit,
+	)
+}
+
+func RemoteUnavailable(remote string, msg interface{}) {
+	fmt.Printf("%vRemote %v is unavailable, a pushed release is detected by remote-tracking branches: %v%v\n",
+		string(colorYellow), remote, msg, string(colorReset),
+	)
+}
+
+func UndoingRemote(remote string) {
+	fmt.Printf("Undoing release on remote %v%v%v...\n",
+		string(colorCyan), remote, string(colorReset),
+	)
+}
+
+func RestoringFile(filepath string) {
+	fmt.Printf("  Restoring %v\n", filepath)
+}
+
+func ResettingBranch(name, commit string) {
+	fmt.Printf("Resetting branch %v%v%v to %v...\n",
+		string(colorCyan), name, string(colorReset), commit,
+	)
+}
+
+func DeletingTag(name string) {
+	fmt.Printf("Deleting tag %v%v%v...\n",
+		string(colorCyan), name, string(colorReset),
+	)
+}
+
+func DeletingBranch(name string) {
+	fmt.Printf("Deleting release branch %v%v%v...\n",
+		string(colorCyan), name, string(colorReset),
 	)
 }
 
@@ -56,6 +270,10 @@ func Error(msg interface{}) {
 }
 
 func Fatal(msg interface{}) {
+	FatalWithCode(msg, 1)
+}
+
+func FatalWithCode(msg interface{}, code int) {
 	Error(msg)
-	os.Exit(1)
+	os.Exit(code)
 }
@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const context int = 3
+
+type line struct {
+	Operation diffmatchpatch.Operation
+	Text      string
+}
+
+// Unified returns a unified diff of two versions of a file, or an empty string when they are equal
+func Unified(filepath, before, after string) string {
+	if before == after {
+		return ""
+	}
+
+	lines := make([]line, 0)
+	for _, d := range diff.Do(before, after) {
+		for _, l := range strings.SplitAfter(d.Text, "\n") {
+			if l != "" {
+				lines = append(lines, line{Operation: d.Type, Text: l})
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%v\n+++ b/%v\n", filepath, filepath)
+
+	for start := 0; start < len(lines); {
+		first := nextChange(lines, start)
+		if first == -1 {
+			break
+		}
+
+		// extend a hunk while the following change is within the context range
+		last := first
+		for {
+			n := nextChange(lines, last+1)
+			if n == -1 || n-last > context*2 {
+				break
+			}
+			last = n
+		}
+
+		from := max(first-context, 0)
+		to := min(last+context+1, len(lines))
+		writeHunk(&sb, lines, from, to)
+		start = to
+	}
+
+	return sb.String()
+}
+
+func nextChange(lines []line, from int) int {
+	for i := from; i < len(lines); i++ {
+		if lines[i].Operation != diffmatchpatch.DiffEqual {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func writeHunk(sb *strings.Builder, lines []line, from, to int) {
+	var oldStart, newStart int
+	for _, l := range lines[:from] {
+		if l.Operation != diffmatchpatch.DiffInsert {
+			oldStart++
+		}
+		if l.Operation != diffmatchpatch.DiffDelete {
+			newStart++
+		}
+	}
+
+	var oldCount, newCount int
+	for _, l := range lines[from:to] {
+		if l.Operation != diffmatchpatch.DiffInsert {
+			oldCount++
+		}
+		if l.Operation != diffmatchpatch.DiffDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%v +%v @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, l := range lines[from:to] {
+		switch l.Operation {
+		case diffmatchpatch.DiffDelete:
+			sb.WriteString("-")
+		case diffmatchpatch.DiffInsert:
+			sb.WriteString("+")
+		default:
+			sb.WriteString(" ")
+		}
+
+		sb.WriteString(l.Text)
+		if !strings.HasSuffix(l.Text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%v,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%v", start+1)
+	}
+
+	return fmt.Sprintf("%v,%v", start+1, count)
+}
@@ -0,0 +1,92 @@
+package diff_test
+
+import (
+	"testing"
+	"version-bump/diff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified(t *testing.T) {
+	a := assert.New(t)
+
+	type test struct {
+		Before         string
+		After          string
+		ExpectedResult string
+	}
+
+	suite := map[string]test{
+		"Equal": {
+			Before:         "a\nb\n",
+			After:          "a\nb\n",
+			ExpectedResult: "",
+		},
+		"Single Line": {
+			Before: "1.2.3\n",
+			After:  "2.0.0\n",
+			ExpectedResult: `--- a/file
++++ b/file
+@@ -1 +1 @@
+-1.2.3
++2.0.0
+`,
+		},
+		"Context": {
+			Before: "1\n2\n3\n4\nversion=1.2.3\n6\n7\n8\n9\n",
+			After:  "1\n2\n3\n4\nversion=1.3.0\n6\n7\n8\n9\n",
+			ExpectedResult: `--- a/file
++++ b/file
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-version=1.2.3
++version=1.3.0
+ 6
+ 7
+ 8
+`,
+		},
+		"Multiple Hunks": {
+			Before: "v=1\n2\n3\n4\n5\n6\n7\n8\n9\nv=1\n",
+			After:  "v=2\n2\n3\n4\n5\n6\n7\n8\n9\nv=2\n",
+			ExpectedResult: `--- a/file
++++ b/file
+@@ -1,4 +1,4 @@
+-v=1
++v=2
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-v=1
++v=2
+`,
+		},
+		"Missing Trailing Newline": {
+			Before: "a\nv=1",
+			After:  "a\nv=2",
+			ExpectedResult: `--- a/file
++++ b/file
+@@ -1,2 +1,2 @@
+ a
+-v=1
+\ No newline at end of file
++v=2
+\ No newline at end of file
+`,
+		},
+	}
+
+	var counter int
+	for name, test := range suite {
+		counter++
+		t.Logf("Test Case %v/%v - %s", counter, len(suite), name)
+
+		a.Equal(test.ExpectedResult, diff.Unified("file", test.Before, test.After))
+	}
+}
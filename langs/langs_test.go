@@ -15,6 +15,7 @@ func TestNew(t *testing.T) {
 	var dockerRegex = []string{
 		fmt.Sprintf("^LABEL .*org.opencontainers.image.version['\"= ]*[vV]?(?P<version>%v)['\"]?.*", changelog.SemVerRegex),
 		fmt.Sprintf("^\\s*['\"]?org.opencontainers.image.version['\"= ]*[vV]?(?P<version>%v)['\"]?.*", changelog.SemVerRegex),
+		fmt.Sprintf("^ENV\\s+(?:.*\\s)?['\"]?VERSION['\"= ]*[vV]?(?P<version>%v)['\"]?.*", changelog.SemVerRegex),
 	}
 
 	var golangRegex = []string{
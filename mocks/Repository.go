@@ -4,9 +4,11 @@ package mocks
 
 import (
 	git "github.com/go-git/go-git/v5"
-	mock "github.com/stretchr/testify/mock"
-
+	config "github.com/go-git/go-git/v5/config"
 	plumbing "github.com/go-git/go-git/v5/plumbing"
+	object "github.com/go-git/go-git/v5/plumbing/object"
+	storer "github.com/go-git/go-git/v5/plumbing/storer"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -14,6 +16,32 @@ type Repository struct {
 	mock.Mock
 }
 
+// Branch provides a mock function with given fields: _a0
+func (_m *Repository) Branch(_a0 string) (*config.Branch, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *config.Branch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*config.Branch, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *config.Branch); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*config.Branch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTag provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Repository) CreateTag(_a0 string, _a1 plumbing.Hash, _a2 *git.CreateTagOptions) (*plumbing.Reference, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -40,6 +68,178 @@ func (_m *Repository) CreateTag(_a0 string, _a1 plumbing.Hash, _a2 *git.CreateTa
 	return r0, r1
 }
 
+// DeleteTag provides a mock function with given fields: _a0
+func (_m *Repository) DeleteTag(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: _a0
+func (_m *Repository) Fetch(_a0 *git.FetchOptions) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*git.FetchOptions) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Head provides a mock function with given fields:
+func (_m *Repository) Head() (*plumbing.Reference, error) {
+	ret := _m.Called()
+
+	var r0 *plumbing.Reference
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*plumbing.Reference, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *plumbing.Reference); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*plumbing.Reference)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Log provides a mock function with given fields: _a0
+func (_m *Repository) Log(_a0 *git.LogOptions) (object.CommitIter, error) {
+	ret := _m.Called(_a0)
+
+	var r0 object.CommitIter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*git.LogOptions) (object.CommitIter, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*git.LogOptions) object.CommitIter); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(object.CommitIter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*git.LogOptions) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Push provides a mock function with given fields: _a0
+func (_m *Repository) Push(_a0 *git.PushOptions) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*git.PushOptions) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Remote provides a mock function with given fields: _a0
+func (_m *Repository) Remote(_a0 string) (*git.Remote, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *git.Remote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*git.Remote, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *git.Remote); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*git.Remote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TagObject provides a mock function with given fields: _a0
+func (_m *Repository) TagObject(_a0 plumbing.Hash) (*object.Tag, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *object.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(plumbing.Hash) (*object.Tag, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(plumbing.Hash) *object.Tag); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*object.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(plumbing.Hash) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Tags provides a mock function with given fields:
+func (_m *Repository) Tags() (storer.ReferenceIter, error) {
+	ret := _m.Called()
+
+	var r0 storer.ReferenceIter
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (storer.ReferenceIter, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() storer.ReferenceIter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storer.ReferenceIter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Worktree provides a mock function with given fields:
 func (_m *Repository) Worktree() (*git.Worktree, error) {
 	ret := _m.Called()
@@ -4,9 +4,8 @@ package mocks
 
 import (
 	git "github.com/go-git/go-git/v5"
-	mock "github.com/stretchr/testify/mock"
-
 	plumbing "github.com/go-git/go-git/v5/plumbing"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // Worktree is an autogenerated mock type for the Worktree type
@@ -66,6 +65,46 @@ func (_m *Worktree) Commit(_a0 string, _a1 *git.CommitOptions) (plumbing.Hash, e
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: _a0
+func (_m *Worktree) Restore(_a0 *git.RestoreOptions) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*git.RestoreOptions) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Status provides a mock function with given fields:
+func (_m *Worktree) Status() (git.Status, error) {
+	ret := _m.Called()
+
+	var r0 git.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (git.Status, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() git.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(git.Status)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWorktree creates a new instance of Worktree. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWorktree(t interface {